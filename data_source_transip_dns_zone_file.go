@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func dataSourceDNSZoneFile() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDNSZoneFileRead,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:         schema.TypeString,
+				Description:  "The name, including the tld of the domain.",
+				Required:     true,
+				ValidateFunc: validateDomainName,
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Description: "The DNS entries of the domain as an RFC 1035 zone file. ALIAS entries are included as ALIAS records.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceDNSZoneFileRead(d *schema.ResourceData, m interface{}) error {
+	domainName := normalizeDomainName(d.Get("domain").(string))
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	dnsEntries, err := repository.GetDNSEntries(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to read DNS record entries for domain %s: %s", domainName, err)
+	}
+
+	d.SetId(domainName)
+	d.Set("content", renderZoneFile(domainName, dnsEntries))
+
+	return nil
+}
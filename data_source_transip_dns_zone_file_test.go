@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccTransipDataSourceDNSZoneFile(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	var testConfig = `data "transip_dns_zone_file" "test" {domain = "%s"}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestMatchResourceAttr("data.transip_dns_zone_file.test", "content", regexp.MustCompile(fmt.Sprintf(`^\$ORIGIN %s\.\n`, regexp.QuoteMeta(domain)))),
+				),
+			},
+		},
+	})
+}
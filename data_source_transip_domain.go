@@ -13,9 +13,15 @@ func dataSourceDomain() *schema.Resource {
 		Read: dataSourceDomainRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
+				Type:         schema.TypeString,
+				Description:  "The name, including the tld of this domain.",
+				Required:     true,
+				ValidateFunc: validateDomainName,
+			},
+			"unicode_name": {
 				Type:        schema.TypeString,
-				Description: "The name, including the tld of this domain.",
-				Required:    true,
+				Description: "The name of this domain in unicode, for internationalized domain names.",
+				Computed:    true,
 			},
 			"nameservers": {
 				Type:        schema.TypeList,
@@ -78,7 +84,7 @@ func dataSourceDomain() *schema.Resource {
 }
 
 func dataSourceDomainRead(d *schema.ResourceData, m interface{}) error {
-	name := d.Get("name").(string)
+	name := normalizeDomainName(d.Get("name").(string))
 
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
@@ -102,10 +108,15 @@ func dataSourceDomainRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.SetId(i.Name)
+	d.Set("unicode_name", domainNameToUnicode(i.Name))
 	d.Set("nameservers", nameservers)
 	d.Set("tags", i.Tags)
 	d.Set("authcode", i.AuthCode)
-	d.Set("cancellation_date", i.CancellationDate)
+	if i.CancellationDate.Time.IsZero() {
+		d.Set("cancellation_date", "")
+	} else {
+		d.Set("cancellation_date", i.CancellationDate.Time.Format("2006-01-02 15:04:05"))
+	}
 	d.Set("cancellation_status", i.CancellationStatus)
 	d.Set("is_dns_only", i.IsDNSOnly)
 	d.Set("is_transfer_locked", i.IsTransferLocked)
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func dataSourceDomainAction() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDomainActionRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Description:  "The name, including the tld of the domain.",
+				Required:     true,
+				ValidateFunc: validateDomainName,
+			},
+			"running": {
+				Type:        schema.TypeBool,
+				Description: "Whether a domain action is running, or has failed, for the domain.",
+				Computed:    true,
+			},
+			"action": {
+				Type:        schema.TypeString,
+				Description: "The name of the current domain action, empty when there is none.",
+				Computed:    true,
+			},
+			"has_failed": {
+				Type:        schema.TypeBool,
+				Description: "If the current domain action has failed.",
+				Computed:    true,
+			},
+			"message": {
+				Type:        schema.TypeString,
+				Description: "If the current domain action has failed, a message describing why.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceDomainActionRead(d *schema.ResourceData, m interface{}) error {
+	name := normalizeDomainName(d.Get("name").(string))
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+	action, err := getDomainAction(repository, name)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(name)
+	if action == nil {
+		action = &domain.Action{}
+	}
+	d.Set("running", action.Name != "")
+	d.Set("action", action.Name)
+	d.Set("has_failed", action.HasFailed)
+	d.Set("message", action.Message)
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccTransipDataSourceDomainAction(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	var testConfig = `data "transip_domain_action" "test" {name = "%s"}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transip_domain_action.test", "running", "false"),
+					resource.TestCheckResourceAttr("data.transip_domain_action.test", "has_failed", "false"),
+				),
+			},
+		},
+	})
+}
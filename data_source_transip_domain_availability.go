@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+// Maximum number of domains the availability API checks in a single request
+const domainAvailabilityBatchSize = 20
+
+func dataSourceDomainAvailability() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDomainAvailabilityRead,
+		Schema: map[string]*schema.Schema{
+			"domain_names": {
+				Type:        schema.TypeList,
+				Description: "The names, including the tld, of the domains to check.",
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"availability": {
+				Type:        schema.TypeList,
+				Description: "The availability of each domain, in the order of domain_names.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain_name": {
+							Type:        schema.TypeString,
+							Description: "The name of the domain.",
+							Computed:    true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The status of the domain, either 'inyouraccount', 'unavailable', 'notfree', 'free', 'internalpull' or 'internalpush'.",
+							Computed:    true,
+						},
+						"available": {
+							Type:        schema.TypeBool,
+							Description: "Whether the domain is free to register.",
+							Computed:    true,
+						},
+						"actions": {
+							Type:        schema.TypeList,
+							Description: "The actions that can be performed on the domain, like 'register' or 'transfer'.",
+							Computed:    true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDomainAvailabilityRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	var domainNames []string
+	for _, v := range d.Get("domain_names").([]interface{}) {
+		domainNames = append(domainNames, normalizeDomainName(v.(string)))
+	}
+
+	// The API does not return the results in the order of the request
+	results := make(map[string]domain.Availability)
+	for start := 0; start < len(domainNames); start += domainAvailabilityBatchSize {
+		end := start + domainAvailabilityBatchSize
+		if end > len(domainNames) {
+			end = len(domainNames)
+		}
+
+		batch, err := repository.GetAvailabilityForMultipleDomains(domainNames[start:end])
+		if err != nil {
+			return fmt.Errorf("failed to get availability of domains %s: %s", strings.Join(domainNames[start:end], ", "), err)
+		}
+		for _, result := range batch {
+			results[normalizeDomainName(result.DomainName)] = result
+		}
+	}
+
+	var availability []map[string]interface{}
+	for _, domainName := range domainNames {
+		result, ok := results[domainName]
+		if !ok {
+			return fmt.Errorf("no availability returned for domain %q", domainName)
+		}
+
+		var actions []string
+		for _, action := range result.Actions {
+			actions = append(actions, string(action))
+		}
+		availability = append(availability, map[string]interface{}{
+			"domain_name": result.DomainName,
+			"status":      string(result.Status),
+			"available":   result.Status == domain.AvailabilityStatusFree,
+			"actions":     actions,
+		})
+	}
+
+	d.SetId(strings.Join(domainNames, ","))
+	d.Set("availability", availability)
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccTransipDataSourceDomainAvailability(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	var testConfig = `data "transip_domain_availability" "test" {domain_names = ["%s"]}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transip_domain_availability.test", "availability.0.domain_name", domain),
+					resource.TestCheckResourceAttr("data.transip_domain_availability.test", "availability.0.status", "inyouraccount"),
+					resource.TestCheckResourceAttr("data.transip_domain_availability.test", "availability.0.available", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataSourceDomainAvailabilityOrder(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		return `{"availability":[
+			{"domainName":"example.nl","status":"free","actions":["register"]},
+			{"domainName":"example.com","status":"notfree","actions":["transfer"]}
+		]}`, nil
+	}}
+
+	d := schema.TestResourceDataRaw(t, dataSourceDomainAvailability().Schema, map[string]interface{}{
+		"domain_names": []interface{}{"Example.com", "example.nl"},
+	})
+	if err := dataSourceDomainAvailabilityRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"availability.0.domain_name": "example.com",
+		"availability.0.available":   "false",
+		"availability.1.domain_name": "example.nl",
+		"availability.1.available":   "true",
+	}
+	for k, v := range expected {
+		if got := fmt.Sprint(d.Get(k)); got != v {
+			t.Errorf("expected %s to be %q, got %q", k, v, got)
+		}
+	}
+
+	client.handler = func(r fakeRequest) (string, error) {
+		return `{"availability":[{"domainName":"example.com","status":"notfree"}]}`, nil
+	}
+	if err := dataSourceDomainAvailabilityRead(d, client); err == nil {
+		t.Error("expected an error for the missing domain")
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/sslcertificate"
+)
+
+func dataSourceDomainSSLCertificates() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDomainSSLCertificatesRead,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:         schema.TypeString,
+				Description:  "The name, including the tld of the domain.",
+				Required:     true,
+				ValidateFunc: validateDomainName,
+			},
+			"include_pem": {
+				Type:        schema.TypeBool,
+				Description: "Download the certificate, CA bundle and private key of active certificates.",
+				Optional:    true,
+				Default:     false,
+			},
+			"certificates": {
+				Type:        schema.TypeList,
+				Description: "The SSL certificates of the domain.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"certificate_id": {
+							Type:        schema.TypeInt,
+							Description: "The id of the certificate.",
+							Computed:    true,
+						},
+						"common_name": {
+							Type:        schema.TypeString,
+							Description: "The domain name the certificate is issued for, starts with '*.' for a wildcard certificate.",
+							Computed:    true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The current status, either 'active', 'inactive' or 'expired'.",
+							Computed:    true,
+						},
+						"expiration_date": {
+							Type:        schema.TypeString,
+							Description: "The expiration date of the certificate.",
+							Computed:    true,
+						},
+						"certificate": {
+							Type:        schema.TypeString,
+							Description: "The PEM encoded certificate, only with include_pem.",
+							Computed:    true,
+							Sensitive:   true,
+						},
+						"ca_bundle": {
+							Type:        schema.TypeString,
+							Description: "The PEM encoded CA bundle, only with include_pem.",
+							Computed:    true,
+							Sensitive:   true,
+						},
+						"private_key": {
+							Type:        schema.TypeString,
+							Description: "The PEM encoded private key, only with include_pem.",
+							Computed:    true,
+							Sensitive:   true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDomainSSLCertificatesRead(d *schema.ResourceData, m interface{}) error {
+	domainName := normalizeDomainName(d.Get("domain").(string))
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+	sslRepository := sslcertificate.Repository{Client: client}
+
+	certificates, err := repository.GetSSLCertificates(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to get SSL certificates of domain %q: %s", domainName, err)
+	}
+
+	maps := make([]map[string]interface{}, len(certificates))
+	for i, c := range certificates {
+		maps[i] = map[string]interface{}{
+			"certificate_id":  c.CertificateID,
+			"common_name":     c.CommonName,
+			"status":          c.Status,
+			"expiration_date": c.ExpirationDate,
+		}
+
+		// Only active certificates can be downloaded
+		if !d.Get("include_pem").(bool) || c.Status != "active" {
+			continue
+		}
+		data, err := sslRepository.Download(c.CertificateID)
+		if err != nil {
+			return fmt.Errorf("failed to download SSL certificate %d of domain %q: %s", c.CertificateID, domainName, err)
+		}
+		maps[i]["certificate"] = data.CertificateCrt
+		maps[i]["ca_bundle"] = data.CaBundleCrt
+		maps[i]["private_key"] = data.CertificateKey
+	}
+
+	d.SetId(domainName)
+	err = d.Set("certificates", maps)
+	if err != nil {
+		return fmt.Errorf("failed to set SSL certificates of domain %q: %s", domainName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccTransipDataSourceDomainSSLCertificates(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	var testConfig = `data "transip_domain_ssl_certificates" "test" {domain = "%s"}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testConfig, domain),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transip_domain_ssl_certificates.test", "domain", domain),
+					resource.TestCheckResourceAttrSet("data.transip_domain_ssl_certificates.test", "certificates.#"),
+				),
+			},
+		},
+	})
+}
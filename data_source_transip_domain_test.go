@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
 
 func TestAccTransipDataSourceDomain(t *testing.T) {
@@ -28,3 +29,26 @@ func TestAccTransipDataSourceDomain(t *testing.T) {
 		},
 	})
 }
+
+func TestDataSourceDomainPendingCancellation(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch r.endpoint {
+		case "/domains/example.com":
+			return `{"domain":{"name":"example.com","cancellationStatus":"cancelled","cancellationDate":"2026-12-31 00:00:00"}}`, nil
+		case "/domains/example.com/nameservers":
+			return `{"nameservers":[]}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s", r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, dataSourceDomain().Schema, map[string]interface{}{"name": "example.com"})
+	if err := dataSourceDomainRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Get("cancellation_status").(string); got != "cancelled" {
+		t.Errorf("expected cancellation_status cancelled, got %q", got)
+	}
+	if got := d.Get("cancellation_date").(string); got != "2026-12-31 00:00:00" {
+		t.Errorf("expected cancellation_date 2026-12-31 00:00:00, got %q", got)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func dataSourceDomainWhois() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDomainWhoisRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Description:  "The name, including the tld of the domain.",
+				Required:     true,
+				ValidateFunc: validateDomainName,
+			},
+			"whois": {
+				Type:        schema.TypeString,
+				Description: "The raw WHOIS output of the domain.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceDomainWhoisRead(d *schema.ResourceData, m interface{}) error {
+	name := normalizeDomainName(d.Get("name").(string))
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+	whois, err := repository.GetWHOIS(name)
+	if err != nil {
+		return fmt.Errorf("failed to get WHOIS of domain %q: %s", name, err)
+	}
+
+	d.SetId(name)
+	d.Set("whois", whois)
+
+	return nil
+}
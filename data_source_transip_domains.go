@@ -3,44 +3,330 @@ package main
 import (
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/transip/gotransip/v6/domain"
 	"github.com/transip/gotransip/v6/repository"
 )
 
+// Number of domains requested per page when listing all domains
+const domainsPageSize = 100
+
+// Filters of the transip_domains data source, unset filters match any domain
+type domainFilter struct {
+	tags             []string
+	tld              string
+	renewalAfter     time.Time
+	renewalBefore    time.Time
+	isDNSOnly        *bool
+	isTransferLocked *bool
+}
+
 func dataSourceDomains() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceDomainsRead,
 		Schema: map[string]*schema.Schema{
+			"tags": {
+				Type:        schema.TypeList,
+				Description: "Only return domains that have all of these tags.",
+				Optional:    true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"tld": {
+				Type:        schema.TypeString,
+				Description: "Only return domains under this tld, for example '.nl'.",
+				Optional:    true,
+			},
+			"renewal_date_after": {
+				Type:         schema.TypeString,
+				Description:  "Only return domains renewing on or after this date, in YYYY-mm-dd format.",
+				Optional:     true,
+				ValidateFunc: validateDate,
+			},
+			"renewal_date_before": {
+				Type:         schema.TypeString,
+				Description:  "Only return domains renewing on or before this date, in YYYY-mm-dd format.",
+				Optional:     true,
+				ValidateFunc: validateDate,
+			},
+			"renews_within_days": {
+				Type:         schema.TypeInt,
+				Description:  "Only return domains renewing within this number of days from now.",
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"is_dns_only": {
+				Type:        schema.TypeBool,
+				Description: "Only return domains that are, or are not, DNS only.",
+				Optional:    true,
+			},
+			"is_transfer_locked": {
+				Type:        schema.TypeBool,
+				Description: "Only return domains that are, or are not, locked for transfer at the registry.",
+				Optional:    true,
+			},
 			"domains": {
 				Type:        schema.TypeList,
-				Description: "List of all domain names in your TransIP account.",
+				Description: "List of the names of all matching domains in your TransIP account.",
 				Computed:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"domain_details": {
+				Type:        schema.TypeList,
+				Description: "The matching domains in your TransIP account. Nameservers are not included, use the transip_domain data source for them.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: domainDetailsSchema(),
+				},
+			},
 		},
 	}
 }
 
+// The fields of a domain as returned by the domain list. The nameservers are
+// left out, they are not part of the list and would need a request per domain,
+// use the transip_domain data source for them.
+func domainDetailsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name, including the tld of this domain.",
+			Computed:    true,
+		},
+		"unicode_name": {
+			Type:        schema.TypeString,
+			Description: "The name of this domain in unicode, for internationalized domain names.",
+			Computed:    true,
+		},
+		"tags": {
+			Type:        schema.TypeList,
+			Description: "The custom tags added to this domain.",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"authcode": {
+			Type:        schema.TypeString,
+			Description: "The authcode for this domain as generated by the registry.",
+			Computed:    true,
+			Sensitive:   true,
+		},
+		"cancellation_date": {
+			Type:        schema.TypeString,
+			Description: "Cancellation data, in YYYY-mm-dd h:i:s format, empty if the domain is active.",
+			Computed:    true,
+		},
+		"cancellation_status": {
+			Type:        schema.TypeString,
+			Description: "Cancellation status, empty if the domain is active, 'cancelled' when the domain is cancelled.",
+			Computed:    true,
+		},
+		"is_dns_only": {
+			Type:        schema.TypeBool,
+			Description: "Whether this domain is DNS only.",
+			Computed:    true,
+		},
+		"is_transfer_locked": {
+			Type:        schema.TypeBool,
+			Description: "If this domain supports transfer locking, this flag is true when the domains ability to transfer is locked at the registry.",
+			Computed:    true,
+		},
+		"is_whitelabel": {
+			Type:        schema.TypeBool,
+			Description: "If this domain is added to your whitelabel.",
+			Computed:    true,
+		},
+		"registration_date": {
+			Type:        schema.TypeString,
+			Description: "Registration date of the domain, in YYYY-mm-dd format.",
+			Computed:    true,
+		},
+		"renewal_date": {
+			Type:        schema.TypeString,
+			Description: "Next renewal date of the domain, in YYYY-mm-dd format.",
+			Computed:    true,
+		},
+	}
+}
+
+func validateDate(v interface{}, k string) (ws []string, errs []error) {
+	if _, err := time.Parse(dateFormat, v.(string)); err != nil {
+		errs = append(errs, fmt.Errorf("%q must be a date in YYYY-mm-dd format: %s", k, err))
+	}
+	return
+}
+
+// Format of dates returned by the API
+const dateFormat = "2006-01-02"
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateFormat)
+}
+
+func domainToMap(i domain.Domain) map[string]interface{} {
+	cancellationDate := ""
+	if !i.CancellationDate.Time.IsZero() {
+		cancellationDate = i.CancellationDate.Time.Format("2006-01-02 15:04:05")
+	}
+	return map[string]interface{}{
+		"name":                i.Name,
+		"unicode_name":        domainNameToUnicode(i.Name),
+		"tags":                i.Tags,
+		"authcode":            i.AuthCode,
+		"cancellation_date":   cancellationDate,
+		"cancellation_status": i.CancellationStatus,
+		"is_dns_only":         i.IsDNSOnly,
+		"is_transfer_locked":  i.IsTransferLocked,
+		"is_whitelabel":       i.IsWhitelabel,
+		"registration_date":   formatDate(i.RegistrationDate.Time),
+		"renewal_date":        formatDate(i.RenewalDate.Time),
+	}
+}
+
+func (f domainFilter) matches(i domain.Domain) bool {
+	for _, tag := range f.tags {
+		found := false
+		for _, t := range i.Tags {
+			if t == tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.tld != "" && !strings.HasSuffix(strings.ToLower(i.Name), f.tld) {
+		return false
+	}
+
+	renewal := i.RenewalDate.Time
+	if !f.renewalAfter.IsZero() && (renewal.IsZero() || renewal.Before(f.renewalAfter)) {
+		return false
+	}
+	if !f.renewalBefore.IsZero() && (renewal.IsZero() || renewal.After(f.renewalBefore)) {
+		return false
+	}
+
+	if f.isDNSOnly != nil && i.IsDNSOnly != *f.isDNSOnly {
+		return false
+	}
+	if f.isTransferLocked != nil && i.IsTransferLocked != *f.isTransferLocked {
+		return false
+	}
+
+	return true
+}
+
+func dataSourceDomainsFilter(d *schema.ResourceData, now time.Time) domainFilter {
+	var f domainFilter
+
+	for _, v := range d.Get("tags").([]interface{}) {
+		f.tags = append(f.tags, v.(string))
+	}
+	if v, ok := d.GetOk("tld"); ok {
+		f.tld = "." + strings.TrimPrefix(strings.ToLower(v.(string)), ".")
+	}
+
+	if v, ok := d.GetOk("renewal_date_after"); ok {
+		f.renewalAfter, _ = time.Parse(dateFormat, v.(string))
+	}
+	if v, ok := d.GetOk("renewal_date_before"); ok {
+		f.renewalBefore, _ = time.Parse(dateFormat, v.(string))
+	}
+	// renewal dates have no time of day, so compare against the start of the day
+	if v, ok := d.GetOkExists("renews_within_days"); ok {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		before := today.AddDate(0, 0, v.(int))
+		if f.renewalBefore.IsZero() || before.Before(f.renewalBefore) {
+			f.renewalBefore = before
+		}
+		if f.renewalAfter.IsZero() || today.After(f.renewalAfter) {
+			f.renewalAfter = today
+		}
+	}
+
+	if v, ok := d.GetOkExists("is_dns_only"); ok {
+		b := v.(bool)
+		f.isDNSOnly = &b
+	}
+	if v, ok := d.GetOkExists("is_transfer_locked"); ok {
+		b := v.(bool)
+		f.isTransferLocked = &b
+	}
+
+	return f
+}
+
+// All domains in the account. Paging stops at the first short page, or at a
+// page without new domains in case the API ignores the page parameter.
+func getAllDomains(repository domain.Repository) ([]domain.Domain, error) {
+	var domains []domain.Domain
+	seen := make(map[string]bool)
+	for page := 1; ; page++ {
+		selection, err := repository.GetSelection(page, domainsPageSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get page %d of all domains: %s", page, err)
+		}
+		log.Printf("[DEBUG] terraform-provider-transip: requesting all domains, page %d: %d domains\n", page, len(selection))
+
+		found := false
+		for _, i := range selection {
+			if seen[i.Name] {
+				continue
+			}
+			seen[i.Name] = true
+			found = true
+			domains = append(domains, i)
+		}
+		if !found && len(selection) > 0 {
+			log.Printf("[WARN] terraform-provider-transip: page %d of all domains has no new domains, stopping\n", page)
+		}
+		if !found || len(selection) < domainsPageSize {
+			return domains, nil
+		}
+	}
+}
+
 func dataSourceDomainsRead(d *schema.ResourceData, m interface{}) error {
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
-	domains, err := repository.GetAll()
+	domains, err := getAllDomains(repository)
 	if err != nil {
-		return fmt.Errorf("failed to get all domains: %s", err)
+		return err
 	}
-	var domainNames []string
-	for i, domain := range domains {
-		domainNames = append(domainNames, domain.Name)
-		log.Printf(fmt.Sprintf("requesting all domains, %d/%d: %s", i+1, len(domains), domain.Name))
+
+	filter := dataSourceDomainsFilter(d, time.Now())
+
+	domainNames := []string{}
+	details := []map[string]interface{}{}
+	for _, i := range domains {
+		if !filter.matches(i) {
+			continue
+		}
+		domainNames = append(domainNames, i.Name)
+		details = append(details, domainToMap(i))
 	}
 
 	d.SetId(uuid.New().String())
 	d.Set("domains", domainNames)
+	err = d.Set("domain_details", details)
+	if err != nil {
+		return fmt.Errorf("failed to set domain details: %s", err)
+	}
 
 	return nil
 }
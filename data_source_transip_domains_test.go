@@ -1,10 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"os"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/rest"
 )
 
 func TestAccTransipDataSourceDomains(t *testing.T) {
@@ -28,3 +36,109 @@ func TestAccTransipDataSourceDomains(t *testing.T) {
 		},
 	})
 }
+
+func TestDomainFilter(t *testing.T) {
+	date := func(s string) rest.Date {
+		d, _ := time.Parse(dateFormat, s)
+		return rest.Date{Time: d}
+	}
+	domains := []domain.Domain{
+		{Name: "example.nl", Tags: []string{"production", "web"}, RenewalDate: date("2026-11-01"), IsTransferLocked: true},
+		{Name: "example.com", Tags: []string{"production"}, RenewalDate: date("2027-06-01")},
+		{Name: "example.be", IsDNSOnly: true},
+	}
+
+	raw := func(config map[string]interface{}) domainFilter {
+		d := schema.TestResourceDataRaw(t, dataSourceDomains().Schema, config)
+		now, _ := time.Parse(dateFormat, "2026-10-19")
+		return dataSourceDomainsFilter(d, now)
+	}
+
+	tests := []struct {
+		config   map[string]interface{}
+		expected []string
+	}{
+		{map[string]interface{}{}, []string{"example.nl", "example.com", "example.be"}},
+		{map[string]interface{}{"tags": []interface{}{"production"}}, []string{"example.nl", "example.com"}},
+		{map[string]interface{}{"tags": []interface{}{"production", "web"}}, []string{"example.nl"}},
+		{map[string]interface{}{"tld": "com"}, []string{"example.com"}},
+		{map[string]interface{}{"tld": ".NL"}, []string{"example.nl"}},
+		{map[string]interface{}{"renews_within_days": 30}, []string{"example.nl"}},
+		{map[string]interface{}{"renewal_date_after": "2027-01-01"}, []string{"example.com"}},
+		{map[string]interface{}{"renewal_date_before": "2027-01-01"}, []string{"example.nl"}},
+		{map[string]interface{}{"is_dns_only": true}, []string{"example.be"}},
+		{map[string]interface{}{"is_transfer_locked": false}, []string{"example.com", "example.be"}},
+	}
+
+	for _, test := range tests {
+		filter := raw(test.config)
+		var got []string
+		for _, i := range domains {
+			if filter.matches(i) {
+				got = append(got, i.Name)
+			}
+		}
+		if !reflect.DeepEqual(got, test.expected) {
+			t.Errorf("filter %v: expected %v, got %v", test.config, test.expected, got)
+		}
+	}
+}
+
+// A page of domains named domain<n>.com, starting at first
+func domainsPage(first int, count int) string {
+	var domains []string
+	for n := first; n < first+count; n++ {
+		domains = append(domains, fmt.Sprintf(`{"name":"domain%d.com"}`, n))
+	}
+	return fmt.Sprintf(`{"domains":[%s]}`, strings.Join(domains, ","))
+}
+
+func TestGetAllDomains(t *testing.T) {
+	cases := []struct {
+		name     string
+		handler  func(page int) string
+		expected int
+		requests int
+	}{
+		{"single page", func(page int) string { return domainsPage(0, 3) }, 3, 1},
+		{"full pages", func(page int) string {
+			if page > 2 {
+				return domainsPage(0, 0)
+			}
+			return domainsPage((page-1)*domainsPageSize, domainsPageSize)
+		}, 2 * domainsPageSize, 3},
+		{"last page short", func(page int) string {
+			if page == 2 {
+				return domainsPage(domainsPageSize, 5)
+			}
+			return domainsPage(0, domainsPageSize)
+		}, domainsPageSize + 5, 2},
+		{"page ignored", func(page int) string { return domainsPage(0, domainsPageSize) }, domainsPageSize, 2},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+				if r.endpoint != "/domains" {
+					return "", fmt.Errorf("unexpected request %s", r.endpoint)
+				}
+				page, err := strconv.Atoi(r.parameters.Get("page"))
+				if err != nil {
+					return "", err
+				}
+				return c.handler(page), nil
+			}}
+
+			domains, err := getAllDomains(domain.Repository{Client: client})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(domains) != c.expected {
+				t.Errorf("expected %d domains, got %d", c.expected, len(domains))
+			}
+			if len(client.requests) != c.requests {
+				t.Errorf("expected %d requests, got %d", c.requests, len(client.requests))
+			}
+		})
+	}
+}
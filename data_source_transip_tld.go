@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func dataSourceTLD() *schema.Resource {
+	s := tldSchema()
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The name of the TLD, for example '.nl' or 'com'.",
+		Required:    true,
+	}
+
+	return &schema.Resource{
+		Read:   dataSourceTLDRead,
+		Schema: s,
+	}
+}
+
+func dataSourceTLDRead(d *schema.ResourceData, m interface{}) error {
+	name := "." + strings.TrimPrefix(strings.ToLower(d.Get("name").(string)), ".")
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+	tld, err := repository.GetTLDByTLD(name)
+	if err != nil {
+		return fmt.Errorf("failed to get TLD %q: %s", name, err)
+	}
+
+	d.SetId(tld.Name)
+	for k, v := range tldToMap(tld) {
+		if k == "name" {
+			continue
+		}
+		d.Set(k, v)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccTransipDataSourceTLD(t *testing.T) {
+	var testConfig = `data "transip_tld" "test" {name = "nl"}`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.transip_tld.test", "id", ".nl"),
+					resource.TestCheckResourceAttr("data.transip_tld.test", "can_register", "true"),
+				),
+			},
+		},
+	})
+}
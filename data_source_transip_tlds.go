@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func dataSourceTLDs() *schema.Resource {
+	s := tldSchema()
+	s["name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The name of the TLD, including the starting dot.",
+		Computed:    true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceTLDsRead,
+		Schema: map[string]*schema.Schema{
+			"tlds": {
+				Type:        schema.TypeList,
+				Description: "All TLDs offered by TransIP.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: s,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceTLDsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+	tlds, err := repository.GetTLDs()
+	if err != nil {
+		return fmt.Errorf("failed to get all TLDs: %s", err)
+	}
+
+	var maps []map[string]interface{}
+	for _, tld := range tlds {
+		maps = append(maps, tldToMap(tld))
+	}
+
+	d.SetId(uuid.New().String())
+	d.Set("tlds", maps)
+
+	return nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/vps"
+)
+
+func dataSourceVpsAddons() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVpsAddonsRead,
+		Schema: map[string]*schema.Schema{
+			"vps_name": {
+				Type:        schema.TypeString,
+				Description: "The unique VPS name.",
+				Required:    true,
+			},
+			"active": {
+				Type:        schema.TypeList,
+				Description: "The addons that are active on the VPS.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: productSchema(),
+				},
+			},
+			"available": {
+				Type:        schema.TypeList,
+				Description: "The addons that can be ordered for the VPS.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: productSchema(),
+				},
+			},
+			"cancellable": {
+				Type:        schema.TypeList,
+				Description: "The active addons that can be cancelled.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: productSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVpsAddonsRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("vps_name").(string)
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+	addons, err := repository.GetAddons(name)
+	if err != nil {
+		return fmt.Errorf("failed to get addons of VPS %q: %s", name, err)
+	}
+
+	d.SetId(name)
+	d.Set("active", productsToMaps(addons.Active))
+	d.Set("available", productsToMaps(addons.Available))
+	d.Set("cancellable", productsToMaps(addons.Cancellable))
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/vps"
+)
+
+func dataSourceVpsSnapshots() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVpsSnapshotsRead,
+		Schema: map[string]*schema.Schema{
+			"vps_name": {
+				Type:        schema.TypeString,
+				Description: "The unique VPS name.",
+				Required:    true,
+			},
+			"snapshots": {
+				Type:        schema.TypeList,
+				Description: "The snapshots of the VPS.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the snapshot.",
+							Computed:    true,
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Description: "The description of the snapshot.",
+							Computed:    true,
+						},
+						"date_time_create": {
+							Type:        schema.TypeString,
+							Description: "The creation date of the snapshot.",
+							Computed:    true,
+						},
+						"disk_size": {
+							Type:        schema.TypeInt,
+							Description: "The size of the snapshot in kB.",
+							Computed:    true,
+						},
+						"operating_system": {
+							Type:        schema.TypeString,
+							Description: "The operating system of the snapshot.",
+							Computed:    true,
+						},
+						"status": {
+							Type:        schema.TypeString,
+							Description: "The status of the snapshot, either 'active', 'creating', 'reverting', 'deleting', 'pendingDeletion', 'syncing' or 'moving'.",
+							Computed:    true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVpsSnapshotsRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("vps_name").(string)
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+	snapshots, err := repository.GetSnapshots(name)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshots of VPS %q: %s", name, err)
+	}
+
+	maps := make([]map[string]interface{}, len(snapshots))
+	for i, snapshot := range snapshots {
+		maps[i] = map[string]interface{}{
+			"name":             snapshot.Name,
+			"description":      snapshot.Description,
+			"date_time_create": snapshot.DateTimeCreate,
+			"disk_size":        snapshot.DiskSize,
+			"operating_system": snapshot.OperatingSystem,
+			"status":           snapshot.Status,
+		}
+	}
+
+	d.SetId(name)
+	err = d.Set("snapshots", maps)
+	if err != nil {
+		return fmt.Errorf("failed to set snapshots of VPS %q: %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/vps"
+)
+
+func dataSourceVpsUpgrades() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceVpsUpgradesRead,
+		Schema: map[string]*schema.Schema{
+			"vps_name": {
+				Type:        schema.TypeString,
+				Description: "The unique VPS name.",
+				Required:    true,
+			},
+			"upgrades": {
+				Type:        schema.TypeList,
+				Description: "The products the VPS can be upgraded to in place.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: productSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVpsUpgradesRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("vps_name").(string)
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+	upgrades, err := repository.GetUpgrades(name)
+	if err != nil {
+		return fmt.Errorf("failed to get upgrades of VPS %q: %s", name, err)
+	}
+
+	d.SetId(name)
+	err = d.Set("upgrades", productsToMaps(upgrades))
+	if err != nil {
+		return fmt.Errorf("failed to set upgrades of VPS %q: %s", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/rest"
+)
+
+// Nameserver domains that serve the DNS entries managed through the API
+var transipNameserverDomains = []string{"transip.net", "transip.nl", "transip.eu"}
+
+// The outcome of the authority check per domain, so each domain is only
+// checked once per run no matter how many resources manage its entries.
+var dnsAuthorityResults = struct {
+	sync.Mutex
+	m map[string]error
+}{m: make(map[string]error)}
+
+// Check whether DNS entries of the domain can be edited and are served by the
+// TransIP nameservers. Returns an error in strict mode, the default. Otherwise
+// only a warning is logged as the SDK has no warning diagnostics, so it is
+// only visible with TF_LOG.
+func checkDNSAuthority(m interface{}, domainName string) error {
+	client, ok := m.(repository.Client)
+	if !ok {
+		return nil
+	}
+
+	err := dnsAuthority(client, normalizeDomainName(domainName))
+	if err == nil {
+		return nil
+	}
+
+	if meta, ok := m.(*providerMeta); !ok || meta.strictNameserverCheck {
+		return err
+	}
+	log.Printf("[WARN] terraform-provider-transip: %s\n", err)
+	return nil
+}
+
+// Only the outcome of successful lookups is cached, failed lookups are
+// retried by the next resource that checks the domain.
+func dnsAuthority(client repository.Client, domainName string) error {
+	dnsAuthorityResults.Lock()
+	defer dnsAuthorityResults.Unlock()
+
+	if err, ok := dnsAuthorityResults.m[domainName]; ok {
+		return err
+	}
+
+	repository := domain.Repository{Client: client}
+	i, err := repository.GetByDomainName(domainName)
+	if err != nil {
+		// the domain may be registered by a resource in the same run
+		var restErr *rest.Error
+		if errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound {
+			log.Printf("[DEBUG] terraform-provider-transip: domain %s does not exist yet, skipping nameserver check\n", domainName)
+			return nil
+		}
+		return fmt.Errorf("failed to get domain %q: %s", domainName, err)
+	}
+	// a pending registration or transfer is not cached, it is checked again
+	// once the domain resource has waited for it
+	if !isDNSEditable(i) {
+		return fmt.Errorf("DNS entries of domain %q can not be edited while the domain has status %q", domainName, i.Status)
+	}
+
+	nameservers, err := repository.GetNameservers(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to get nameservers of domain %q: %s", domainName, err)
+	}
+
+	var foreign []string
+	for _, nameserver := range nameservers {
+		if !isTransipNameserver(nameserver.Hostname) {
+			foreign = append(foreign, nameserver.Hostname)
+		}
+	}
+	err = nil
+	if len(foreign) > 0 {
+		err = fmt.Errorf("domain %q uses nameservers %s that are not TransIP nameservers, its DNS entries managed by TransIP are not served by them", domainName, strings.Join(foreign, ", "))
+	}
+
+	dnsAuthorityResults.m[domainName] = err
+	return err
+}
+
+// DNS entries can be edited for DNS only domains and registered domains, not
+// while a registration or transfer is pending or after it was rejected or
+// cancelled. Domains without a status are assumed to be editable.
+func isDNSEditable(i domain.Domain) bool {
+	return i.IsDNSOnly || i.Status == "" || i.Status == "registered"
+}
+
+func isTransipNameserver(hostname string) bool {
+	hostname = normalizeDomainName(hostname)
+	for _, d := range transipNameserverDomains {
+		if isInBailiwick(hostname, d) {
+			return true
+		}
+	}
+	return false
+}
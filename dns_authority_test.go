@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/transip/gotransip/v6/rest"
+)
+
+func TestIsTransipNameserver(t *testing.T) {
+	for _, c := range []struct {
+		hostname string
+		expected bool
+	}{
+		{"ns0.transip.net", true},
+		{"NS1.TransIP.nl.", true},
+		{"ns2.transip.eu", true},
+		{"ns1.nottransip.net", false},
+		{"ns.cloudflare.com", false},
+	} {
+		if actual := isTransipNameserver(c.hostname); actual != c.expected {
+			t.Errorf("expected %v for %s", c.expected, c.hostname)
+		}
+	}
+}
+
+func TestDNSAuthorityDoesNotCacheLookupErrors(t *testing.T) {
+	fail := true
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch r.endpoint {
+		case "/domains/uncached.example":
+			if fail {
+				return "", errors.New("connection reset by peer")
+			}
+			return `{"domain":{"name":"uncached.example"}}`, nil
+		case "/domains/uncached.example/nameservers":
+			return `{"nameservers":[{"hostname":"ns0.transip.net"}]}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s", r.endpoint)
+	}}
+
+	if err := dnsAuthority(client, "uncached.example"); err == nil {
+		t.Fatal("expected the lookup error")
+	}
+
+	fail = false
+	if err := dnsAuthority(client, "uncached.example"); err != nil {
+		t.Fatalf("expected the lookup to be retried, got %s", err)
+	}
+
+	requests := len(client.requests)
+	if err := dnsAuthority(client, "uncached.example"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(client.requests) != requests {
+		t.Error("expected the successful lookup to be cached")
+	}
+}
+
+func TestDNSAuthorityDomainStatus(t *testing.T) {
+	for _, c := range []struct {
+		domain      string
+		response    string
+		expectError bool
+	}{
+		{"registered.example", `{"domain":{"name":"registered.example","status":"registered"}}`, false},
+		{"dnsonly.example", `{"domain":{"name":"dnsonly.example","status":"inProgress","isDnsOnly":true}}`, false},
+		{"pending.example", `{"domain":{"name":"pending.example","status":"inProgress"}}`, true},
+		{"cancelled.example", `{"domain":{"name":"cancelled.example","status":"cancelled"}}`, true},
+	} {
+		client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+			switch r.endpoint {
+			case "/domains/" + c.domain:
+				return c.response, nil
+			case "/domains/" + c.domain + "/nameservers":
+				return `{"nameservers":[{"hostname":"ns0.transip.net"}]}`, nil
+			}
+			return "", fmt.Errorf("unexpected request %s", r.endpoint)
+		}}
+		if err := dnsAuthority(client, c.domain); (err != nil) != c.expectError {
+			t.Errorf("%s: expected error %v, got %v", c.domain, c.expectError, err)
+		}
+	}
+}
+
+func TestDNSAuthorityUnknownDomain(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		return "", &rest.Error{Message: "domain not found", StatusCode: http.StatusNotFound}
+	}}
+	if err := checkDNSAuthority(client, "new.example"); err != nil {
+		t.Errorf("expected a domain that does not exist yet to pass, got %s", err)
+	}
+}
+
+func TestCheckDNSAuthorityStrict(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch r.endpoint {
+		case "/domains/foreign.example":
+			return `{"domain":{"name":"foreign.example","status":"registered"}}`, nil
+		case "/domains/foreign.example/nameservers":
+			return `{"nameservers":[{"hostname":"ns.cloudflare.com"}]}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s", r.endpoint)
+	}}
+
+	if err := checkDNSAuthority(&providerMeta{Client: client, strictNameserverCheck: true}, "foreign.example"); err == nil {
+		t.Error("expected foreign nameservers to fail in strict mode")
+	}
+	if err := checkDNSAuthority(&providerMeta{Client: client}, "foreign.example"); err != nil {
+		t.Errorf("expected only a warning without strict mode, got %s", err)
+	}
+}
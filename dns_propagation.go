@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/transip/gotransip/v6/domain"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Resolver used to check whether DNS entries are served by the authoritative
+// nameservers of a domain. Can be replaced to test against a local nameserver.
+var dnsPropagationResolver dnsResolver = authoritativeResolver{Port: "53", Timeout: 5 * time.Second}
+
+// DNS entry types for which propagation can be checked
+var dnsPropagationTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"NS":    dnsmessage.TypeNS,
+	"SRV":   dnsmessage.TypeSRV,
+	"TXT":   dnsmessage.TypeTXT,
+}
+
+type dnsResolver interface {
+	// Returns the content of all entries of the given type served by the
+	// nameserver for the fully qualified name.
+	LookupDNSEntries(nameserver string, fqdn string, entryType string) ([]string, error)
+}
+
+// Sends non-recursive queries straight to a nameserver, so the answer is not
+// influenced by any caches in between.
+type authoritativeResolver struct {
+	Port    string
+	Timeout time.Duration
+}
+
+func (r authoritativeResolver) LookupDNSEntries(nameserver string, fqdn string, entryType string) ([]string, error) {
+	qtype, ok := dnsPropagationTypes[entryType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported DNS entry type %s", entryType)
+	}
+
+	name, err := dnsmessage.NewName(fqdn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %q: %s", fqdn, err)
+	}
+
+	query := dnsmessage.Message{
+		Header: dnsmessage.Header{ID: uint16(rand.Intn(1 << 16))},
+		Questions: []dnsmessage.Question{
+			{Name: name, Type: qtype, Class: dnsmessage.ClassINET},
+		},
+	}
+
+	address := net.JoinHostPort(nameserver, r.Port)
+	response, err := r.exchange("udp", address, query)
+	if err == nil && response.Truncated {
+		response, err = r.exchange("tcp", address, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if response.RCode != dnsmessage.RCodeSuccess && response.RCode != dnsmessage.RCodeNameError {
+		return nil, fmt.Errorf("nameserver %s returned %s for %s %s", nameserver, response.RCode, entryType, fqdn)
+	}
+
+	var content []string
+	for _, answer := range response.Answers {
+		if answer.Header.Type != qtype || !strings.EqualFold(answer.Header.Name.String(), fqdn) {
+			continue
+		}
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			content = append(content, net.IP(body.A[:]).String())
+		case *dnsmessage.AAAAResource:
+			content = append(content, net.IP(body.AAAA[:]).String())
+		case *dnsmessage.CNAMEResource:
+			content = append(content, body.CNAME.String())
+		case *dnsmessage.MXResource:
+			content = append(content, fmt.Sprintf("%d %s", body.Pref, body.MX.String()))
+		case *dnsmessage.NSResource:
+			content = append(content, body.NS.String())
+		case *dnsmessage.SRVResource:
+			content = append(content, fmt.Sprintf("%d %d %d %s", body.Priority, body.Weight, body.Port, body.Target.String()))
+		case *dnsmessage.TXTResource:
+			content = append(content, strings.Join(body.TXT, ""))
+		}
+	}
+
+	return content, nil
+}
+
+func (r authoritativeResolver) exchange(network string, address string, query dnsmessage.Message) (*dnsmessage.Message, error) {
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout(network, address, r.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nameserver %s: %s", address, err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(r.Timeout))
+
+	// messages over TCP are prefixed with their length
+	if network == "tcp" {
+		packed = append([]byte{byte(len(packed) >> 8), byte(len(packed))}, packed...)
+	}
+	if _, err := conn.Write(packed); err != nil {
+		return nil, fmt.Errorf("failed to query nameserver %s: %s", address, err)
+	}
+
+	var buf []byte
+	if network == "tcp" {
+		var length uint16
+		if err := binary.Read(conn, binary.BigEndian, &length); err != nil {
+			return nil, fmt.Errorf("failed to read response from nameserver %s: %s", address, err)
+		}
+		buf = make([]byte, length)
+		_, err = io.ReadFull(conn, buf)
+	} else {
+		buf = make([]byte, 65535)
+		var n int
+		n, err = conn.Read(buf)
+		buf = buf[:n]
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response from nameserver %s: %s", address, err)
+	}
+
+	var response dnsmessage.Message
+	if err := response.Unpack(buf); err != nil {
+		return nil, fmt.Errorf("invalid response from nameserver %s: %s", address, err)
+	}
+	if response.ID != query.ID {
+		return nil, fmt.Errorf("unexpected response from nameserver %s", address)
+	}
+
+	return &response, nil
+}
+
+// Fully qualified name of a DNS entry name in a domain. Entry names and
+// content can be relative to the domain, '@' being the domain itself.
+func dnsFQDN(domainName string, name string) string {
+	switch {
+	case name == "@" || name == "":
+		return strings.ToLower(domainName) + "."
+	case strings.HasSuffix(name, "."):
+		return strings.ToLower(name)
+	default:
+		return strings.ToLower(name + "." + domainName + ".")
+	}
+}
+
+// Content of a DNS entry in the form the resolver returns it.
+func dnsPropagationContent(domainName string, entryType string, content string) string {
+	fields := strings.Fields(content)
+	switch entryType {
+	case "A", "AAAA":
+		if ip := net.ParseIP(content); ip != nil {
+			return ip.String()
+		}
+	case "CNAME", "NS":
+		return dnsFQDN(domainName, content)
+	case "MX":
+		if len(fields) == 2 {
+			return fmt.Sprintf("%s %s", fields[0], dnsFQDN(domainName, fields[1]))
+		}
+	case "SRV":
+		if len(fields) == 4 {
+			return fmt.Sprintf("%s %s %s %s", fields[0], fields[1], fields[2], dnsFQDN(domainName, fields[3]))
+		}
+	case "TXT":
+		if len(content) > 1 && strings.HasPrefix(content, `"`) && strings.HasSuffix(content, `"`) {
+			return content[1 : len(content)-1]
+		}
+	}
+	return content
+}
+
+// Whether propagation of the DNS entry type can be checked
+func checkDNSPropagationType(entryType string) error {
+	if _, ok := dnsPropagationTypes[entryType]; ok {
+		return nil
+	}
+	var types []string
+	for t := range dnsPropagationTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return fmt.Errorf("wait_for_propagation is not supported for %s records, only for %s records", entryType, strings.Join(types, ", "))
+}
+
+// Wait until all given nameservers serve all content of the record.
+func waitForDNSPropagation(nameservers []domain.Nameserver, domainName string, record dnsRecord, timeout time.Duration) error {
+	if err := checkDNSPropagationType(record.Type); err != nil {
+		return err
+	}
+	if len(record.Content) == 0 {
+		return nil
+	}
+
+	fqdn := dnsFQDN(domainName, record.Name)
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		for _, nameserver := range nameservers {
+			address := nameserver.Hostname
+			if nameserver.IPv4 != nil {
+				address = nameserver.IPv4.String()
+			}
+
+			content, err := dnsPropagationResolver.LookupDNSEntries(address, fqdn, record.Type)
+			if err != nil {
+				return resource.RetryableError(err)
+			}
+
+			served := make(map[string]bool)
+			for _, c := range content {
+				served[dnsPropagationContent(domainName, record.Type, c)] = true
+			}
+
+			for _, c := range record.Content {
+				if !served[dnsPropagationContent(domainName, record.Type, c)] {
+					return resource.RetryableError(fmt.Errorf("nameserver %s does not serve %q for %s record %s yet", nameserver.Hostname, c, record.Type, fqdn))
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/transip/gotransip/v6/domain"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Minimal authoritative nameserver answering queries over UDP from a fixed set of entries
+type testNameserver struct {
+	conn    net.PacketConn
+	mu      sync.Mutex
+	entries map[string]map[dnsmessage.Type][]dnsmessage.ResourceBody
+}
+
+func newTestNameserver(t *testing.T) *testNameserver {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to start test nameserver: %s", err)
+	}
+	ns := &testNameserver{conn: conn, entries: make(map[string]map[dnsmessage.Type][]dnsmessage.ResourceBody)}
+	go ns.serve()
+
+	// point the resolver to the test nameserver for the duration of the test
+	resolver := dnsPropagationResolver
+	_, port, _ := net.SplitHostPort(conn.LocalAddr().String())
+	dnsPropagationResolver = authoritativeResolver{Port: port, Timeout: time.Second}
+	t.Cleanup(func() {
+		dnsPropagationResolver = resolver
+		conn.Close()
+	})
+
+	return ns
+}
+
+func (ns *testNameserver) set(fqdn string, qtype dnsmessage.Type, bodies ...dnsmessage.ResourceBody) {
+	ns.mu.Lock()
+	defer ns.mu.Unlock()
+	if ns.entries[fqdn] == nil {
+		ns.entries[fqdn] = make(map[dnsmessage.Type][]dnsmessage.ResourceBody)
+	}
+	ns.entries[fqdn][qtype] = bodies
+}
+
+func (ns *testNameserver) serve() {
+	buf := make([]byte, 512)
+	for {
+		n, addr, err := ns.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		var query dnsmessage.Message
+		if err := query.Unpack(buf[:n]); err != nil || len(query.Questions) != 1 {
+			continue
+		}
+		question := query.Questions[0]
+
+		response := dnsmessage.Message{
+			Header:    dnsmessage.Header{ID: query.ID, Response: true, Authoritative: true},
+			Questions: query.Questions,
+		}
+		ns.mu.Lock()
+		for _, body := range ns.entries[question.Name.String()][question.Type] {
+			response.Answers = append(response.Answers, dnsmessage.Resource{
+				Header: dnsmessage.ResourceHeader{Name: question.Name, Type: question.Type, Class: dnsmessage.ClassINET, TTL: 300},
+				Body:   body,
+			})
+		}
+		ns.mu.Unlock()
+
+		packed, err := response.Pack()
+		if err != nil {
+			continue
+		}
+		ns.conn.WriteTo(packed, addr)
+	}
+}
+
+func TestAuthoritativeResolverLookup(t *testing.T) {
+	ns := newTestNameserver(t)
+	ns.set("www.example.com.", dnsmessage.TypeA,
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 1}},
+		&dnsmessage.AResource{A: [4]byte{192, 0, 2, 2}},
+	)
+	ns.set("www.example.com.", dnsmessage.TypeTXT, &dnsmessage.TXTResource{TXT: []string{"hello ", "world"}})
+
+	content, err := dnsPropagationResolver.LookupDNSEntries("127.0.0.1", "www.example.com.", "A")
+	if err != nil {
+		t.Fatalf("lookup failed: %s", err)
+	}
+	if !reflect.DeepEqual(content, []string{"192.0.2.1", "192.0.2.2"}) {
+		t.Errorf("unexpected A content %v", content)
+	}
+
+	content, err = dnsPropagationResolver.LookupDNSEntries("127.0.0.1", "www.example.com.", "TXT")
+	if err != nil {
+		t.Fatalf("lookup failed: %s", err)
+	}
+	if !reflect.DeepEqual(content, []string{"hello world"}) {
+		t.Errorf("unexpected TXT content %v", content)
+	}
+}
+
+func TestWaitForDNSPropagation(t *testing.T) {
+	ns := newTestNameserver(t)
+	nameservers := []domain.Nameserver{{Hostname: "ns0.example.net", IPv4: net.ParseIP("127.0.0.1")}}
+	record := dnsRecord{Name: "www", Type: "CNAME", Content: []string{"@"}}
+
+	go func() {
+		time.Sleep(time.Second)
+		target, _ := dnsmessage.NewName("example.com.")
+		ns.set("www.example.com.", dnsmessage.TypeCNAME, &dnsmessage.CNAMEResource{CNAME: target})
+	}()
+
+	if err := waitForDNSPropagation(nameservers, "example.com", record, 10*time.Second); err != nil {
+		t.Fatalf("expected record to propagate: %s", err)
+	}
+
+	record.Content = []string{"other.example.org."}
+	if err := waitForDNSPropagation(nameservers, "example.com", record, 2*time.Second); err == nil {
+		t.Fatal("expected timeout waiting for content that is not served")
+	}
+}
+
+func TestDNSPropagationContent(t *testing.T) {
+	for _, c := range []struct{ entryType, content, expected string }{
+		{"A", "192.0.2.1", "192.0.2.1"},
+		{"AAAA", "2001:DB8:0::1", "2001:db8::1"},
+		{"CNAME", "@", "example.com."},
+		{"CNAME", "www", "www.example.com."},
+		{"CNAME", "Example.ORG.", "example.org."},
+		{"MX", "10 mail", "10 mail.example.com."},
+		{"SRV", "10 100 443 sip.example.org.", "10 100 443 sip.example.org."},
+		{"TXT", `"v=spf1 -all"`, "v=spf1 -all"},
+	} {
+		if actual := dnsPropagationContent("example.com", c.entryType, c.content); actual != c.expected {
+			t.Errorf("expected %q for %s %q, got %q", c.expected, c.entryType, c.content, actual)
+		}
+	}
+}
+
+func TestDNSRecordWaitForPropagationType(t *testing.T) {
+	tests := map[string]bool{
+		"A":     false,
+		"TXT":   false,
+		"CAA":   true,
+		"TLSA":  true,
+		"ALIAS": true,
+	}
+	for entryType, expectError := range tests {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"domain":               "example.com",
+			"name":                 "www",
+			"type":                 entryType,
+			"content":              []interface{}{"example"},
+			"wait_for_propagation": true,
+		})
+		_, err := resourceDNSRecord().Diff(nil, config, nil)
+		if (err != nil) != expectError {
+			t.Errorf("%s: expected error %v, got %v", entryType, expectError, err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/transip/gotransip/v6/rest"
+)
+
+// A request made through the fakeClient
+type fakeRequest struct {
+	method     string
+	endpoint   string
+	parameters url.Values
+	body       string
+}
+
+// Implements repository.Client, answering requests with the JSON returned by
+// the handler, so resources can be tested without the API.
+type fakeClient struct {
+	handler  func(r fakeRequest) (string, error)
+	requests []fakeRequest
+}
+
+func (c *fakeClient) do(method string, request rest.Request) (string, error) {
+	r := fakeRequest{method: method, endpoint: request.Endpoint, parameters: request.Parameters}
+	if request.Body != nil {
+		body, err := request.GetJSONBody()
+		if err != nil {
+			return "", err
+		}
+		r.body = string(body)
+	}
+	c.requests = append(c.requests, r)
+	return c.handler(r)
+}
+
+func (c *fakeClient) Get(request rest.Request, dest interface{}) error {
+	response, err := c.do("GET", request)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(response), dest)
+}
+
+func (c *fakeClient) Put(request rest.Request) error {
+	_, err := c.do("PUT", request)
+	return err
+}
+
+func (c *fakeClient) PutWithResponse(request rest.Request) (rest.Response, error) {
+	_, err := c.do("PUT", request)
+	return rest.Response{}, err
+}
+
+func (c *fakeClient) Post(request rest.Request) error {
+	_, err := c.do("POST", request)
+	return err
+}
+
+func (c *fakeClient) PostWithResponse(request rest.Request) (rest.Response, error) {
+	_, err := c.do("POST", request)
+	return rest.Response{}, err
+}
+
+func (c *fakeClient) Delete(request rest.Request) error {
+	_, err := c.do("DELETE", request)
+	return err
+}
+
+func (c *fakeClient) Patch(request rest.Request) error {
+	_, err := c.do("PATCH", request)
+	return err
+}
+
+func (c *fakeClient) PatchWithResponse(request rest.Request) (rest.Response, error) {
+	_, err := c.do("PATCH", request)
+	return rest.Response{}, err
+}
+
+// The requests made with the method, in order
+func (c *fakeClient) requestsWithMethod(method string) []fakeRequest {
+	var requests []fakeRequest
+	for _, r := range c.requests {
+		if r.method == method {
+			requests = append(requests, r)
+		}
+	}
+	return requests
+}
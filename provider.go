@@ -11,10 +11,17 @@ import (
 
 	"github.com/transip/gotransip/v6"
 	"github.com/transip/gotransip/v6/authenticator"
+	"github.com/transip/gotransip/v6/repository"
 )
 
 var dnsDomainMutexKV = mutexkv.NewMutexKV()
 
+// API client passed to the resources, together with provider wide settings
+type providerMeta struct {
+	repository.Client
+	strictNameserverCheck bool
+}
+
 func envBoolFunc(k string) schema.SchemaDefaultFunc {
 	return func() (interface{}, error) {
 		if v := os.Getenv(k); v == "1" {
@@ -24,6 +31,16 @@ func envBoolFunc(k string) schema.SchemaDefaultFunc {
 	}
 }
 
+// Like envBoolFunc, but true unless the variable is set to "0"
+func envBoolDefaultTrueFunc(k string) schema.SchemaDefaultFunc {
+	return func() (interface{}, error) {
+		if v := os.Getenv(k); v == "0" {
+			return false, nil
+		}
+		return true, nil
+	}
+}
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		Schema: map[string]*schema.Schema{
@@ -59,17 +76,30 @@ func Provider() *schema.Provider {
 				Description: "Use API test mode.",
 				DefaultFunc: envBoolFunc("TRANSIP_TEST_MODE"),
 			},
+			"strict_nameserver_check": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Fail the plan when DNS entries are managed for a domain whose entries can not be edited or that does not use the TransIP nameservers. When disabled, this is only logged as a warning.",
+				DefaultFunc: envBoolDefaultTrueFunc("TRANSIP_STRICT_NAMESERVER_CHECK"),
+			},
 		},
 
 		ConfigureFunc: providerConfigure,
 
 		ResourcesMap: map[string]*schema.Resource{
+			"transip_acme_challenge":             resourceACMEChallenge(),
+			"transip_dns_delegation":             resourceDNSDelegation(),
 			"transip_dns_record":                 resourceDNSRecord(),
+			"transip_dns_zone_file":              resourceDNSZoneFile(),
 			"transip_domain":                     resourceDomain(),
+			"transip_domain_branding":            resourceDomainBranding(),
+			"transip_domain_contacts":            resourceDomainContacts(),
 			"transip_domain_nameservers":         resourceDomainNameservers(),
 			"transip_domain_dnssec":              resourceDomainDNSSec(),
+			"transip_domain_transfer":            resourceDomainTransfer(),
 			"transip_vps":                        resourceVps(),
 			"transip_vps_firewall":               resourceVpsFirewall(),
+			"transip_vps_snapshot":               resourceVpsSnapshot(),
 			"transip_private_network":            resourcePrivateNetwork(),
 			"transip_private_network_attachment": resourcePrivateNetworkAttachment(),
 			"transip_sshkey":                     resourceSSHKey(),
@@ -78,13 +108,23 @@ func Provider() *schema.Provider {
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"transip_domain":            dataSourceDomain(),
-			"transip_domains":           dataSourceDomains(),
-			"transip_vps":               dataSourceVps(),
-			"transip_private_network":   dataSourcePrivateNetwork(),
-			"transip_sshkey":            datasourceSSHKey(),
-			"transip_openstack_project": dataSourceOpenstackProject(),
-			"transip_openstack_user":    dataSourceOpenstackUser(),
+			"transip_dns_zone_file":           dataSourceDNSZoneFile(),
+			"transip_domain":                  dataSourceDomain(),
+			"transip_domain_action":           dataSourceDomainAction(),
+			"transip_domain_availability":     dataSourceDomainAvailability(),
+			"transip_domain_ssl_certificates": dataSourceDomainSSLCertificates(),
+			"transip_domain_whois":            dataSourceDomainWhois(),
+			"transip_domains":                 dataSourceDomains(),
+			"transip_vps":                     dataSourceVps(),
+			"transip_vps_addons":              dataSourceVpsAddons(),
+			"transip_vps_snapshots":           dataSourceVpsSnapshots(),
+			"transip_vps_upgrades":            dataSourceVpsUpgrades(),
+			"transip_private_network":         dataSourcePrivateNetwork(),
+			"transip_sshkey":                  datasourceSSHKey(),
+			"transip_tld":                     dataSourceTLD(),
+			"transip_tlds":                    dataSourceTLDs(),
+			"transip_openstack_project":       dataSourceOpenstackProject(),
+			"transip_openstack_user":          dataSourceOpenstackUser(),
 		},
 	}
 }
@@ -154,5 +194,8 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, err
 	}
 
-	return client, nil
+	return &providerMeta{
+		Client:                client,
+		strictNameserverCheck: d.Get("strict_nameserver_check").(bool),
+	}, nil
 }
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func resourceACMEChallenge() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceACMEChallengeCreate,
+		Read:   resourceACMEChallengeRead,
+		Update: resourceACMEChallengeRead,
+		Delete: resourceACMEChallengeDelete,
+
+		CustomizeDiff: resourceACMEChallengeCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"fqdn": {
+				Type:        schema.TypeString,
+				Description: "The fully qualified challenge name, for example '_acme-challenge.www.example.com'.",
+				Required:    true,
+				ForceNew:    true,
+				StateFunc: func(v interface{}) string {
+					return normalizeDomainName(v.(string))
+				},
+			},
+			"value": {
+				Type:        schema.TypeString,
+				Description: "The TXT value the ACME server expects for the challenge.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"follow_cname": {
+				Type:        schema.TypeBool,
+				Description: "Follow a CNAME on the challenge name to the name the challenge is delegated to.",
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+			},
+			"domain": {
+				Type:             schema.TypeString,
+				Description:      "The domain, including the tld, the TXT entry is added to. Determined from the domains in the account when not set.",
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+				ValidateFunc:     validateDomainName,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the TXT entry in the domain.",
+				Computed:    true,
+			},
+			"expire": {
+				Type:        schema.TypeInt,
+				Description: "The expiration period of the TXT entry, in seconds.",
+				Optional:    true,
+				Default:     60,
+				ForceNew:    true,
+			},
+			"propagation_timeout": {
+				Type:         schema.TypeInt,
+				Description:  "Maximum time in seconds to wait for the authoritative nameservers to serve the challenge.",
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+		},
+	}
+}
+
+// The domain is only checked at plan time when it is configured, otherwise
+// it is determined when the challenge is created.
+func resourceACMEChallengeCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	domainName, ok := d.GetOk("domain")
+	if !ok || !d.NewValueKnown("domain") {
+		return nil
+	}
+	return checkDNSAuthority(m, domainName.(string))
+}
+
+func resourceACMEChallengeCreate(d *schema.ResourceData, m interface{}) error {
+	fqdn := d.Get("fqdn").(string)
+	value := d.Get("value").(string)
+	expire := d.Get("expire").(int)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	if d.Get("follow_cname").(bool) {
+		target, err := net.LookupCNAME(fqdn + ".")
+		if err != nil {
+			// challenge names are usually not there yet, only delegated ones are
+			log.Printf("[DEBUG] terraform-provider-transip: no CNAME for %s: %s\n", fqdn, err)
+		} else if target = normalizeDomainName(target); target != fqdn {
+			log.Printf("[DEBUG] terraform-provider-transip: challenge %s is delegated to %s\n", fqdn, target)
+			fqdn = target
+		}
+	}
+
+	var domainNames []string
+	if v, ok := d.GetOk("domain"); ok {
+		domainNames = []string{v.(string)}
+	} else {
+		domains, err := repository.GetAll()
+		if err != nil {
+			return fmt.Errorf("failed to get all domains: %s", err)
+		}
+		for _, domain := range domains {
+			domainNames = append(domainNames, domain.Name)
+		}
+	}
+
+	domainName, entryName, ok := findDomainForFQDN(domainNames, fqdn)
+	if !ok {
+		return fmt.Errorf("no domain in the account contains challenge name %s", fqdn)
+	}
+
+	dnsEntry := domain.DNSEntry{
+		Name:    entryName,
+		Expire:  expire,
+		Type:    "TXT",
+		Content: value,
+	}
+
+	err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		dnsDomainMutexKV.Lock(domainName)
+		defer dnsDomainMutexKV.Unlock(domainName)
+
+		dnsEntries, err := repository.GetDNSEntries(domainName)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
+		}
+		for _, e := range dnsEntries {
+			if e == dnsEntry {
+				return nil
+			}
+		}
+
+		log.Printf("[DEBUG] terraform-provider-transip: adding challenge %v to %s\n", dnsEntry, domainName)
+		err = repository.AddDNSEntry(domainName, dnsEntry)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to add challenge for domain %s (%v)", domainName, dnsEntry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", domainName, entryName, value))
+	d.Set("domain", domainName)
+	d.Set("name", entryName)
+
+	nameservers, err := repository.GetNameservers(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to get nameservers of domain %q: %s", domainName, err)
+	}
+
+	record := dnsRecord{Name: entryName, Type: "TXT", Expire: expire, Content: []string{value}}
+	timeout := time.Duration(d.Get("propagation_timeout").(int)) * time.Second
+	err = waitForDNSPropagation(nameservers, domainName, record, timeout)
+	if err != nil {
+		return fmt.Errorf("failed waiting for challenge %s to propagate: %s", fqdn, err)
+	}
+
+	return resourceACMEChallengeRead(d, m)
+}
+
+func resourceACMEChallengeRead(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+	entryName := d.Get("name").(string)
+	value := d.Get("value").(string)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	dnsEntries, err := repository.GetDNSEntries(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to read DNS record entries for domain %s: %s", domainName, err)
+	}
+
+	for _, e := range dnsEntries {
+		if e.Name == entryName && e.Type == "TXT" && e.Content == value {
+			return nil
+		}
+	}
+
+	log.Printf("[DEBUG] terraform-provider-transip: challenge %s no longer exists\n", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceACMEChallengeDelete(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+	entryName := d.Get("name").(string)
+	value := d.Get("value").(string)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		dnsDomainMutexKV.Lock(domainName)
+		defer dnsDomainMutexKV.Unlock(domainName)
+
+		dnsEntries, err := repository.GetDNSEntries(domainName)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
+		}
+
+		// only remove our own value, other challenges may be pending for the same name
+		for _, e := range dnsEntries {
+			if e.Name != entryName || e.Type != "TXT" || e.Content != value {
+				continue
+			}
+			log.Printf("[DEBUG] terraform-provider-transip: removing challenge %v from %s\n", e, domainName)
+			err := repository.RemoveDNSEntry(domainName, e)
+			if err != nil {
+				return retryableDNSRecordErrorf(err, "failed to remove challenge for domain %s (%v)", domainName, e)
+			}
+		}
+		return nil
+	})
+}
+
+// Find the domain with the longest name that contains the fully qualified
+// name, and return the entry name relative to that domain.
+func findDomainForFQDN(domainNames []string, fqdn string) (string, string, bool) {
+	fqdn = normalizeDomainName(fqdn)
+
+	var domainName string
+	for _, name := range domainNames {
+		name = normalizeDomainName(name)
+		if (fqdn == name || strings.HasSuffix(fqdn, "."+name)) && len(name) > len(domainName) {
+			domainName = name
+		}
+	}
+	if domainName == "" {
+		return "", "", false
+	}
+
+	if fqdn == domainName {
+		return domainName, "@", true
+	}
+	return domainName, strings.TrimSuffix(fqdn, "."+domainName), true
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccTransipResourceACMEChallenge(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	timestamp := time.Now().Unix()
+	testConfig := fmt.Sprintf(`
+	resource "transip_acme_challenge" "first" {
+		fqdn  = "_acme-challenge.terraform-provider-transip-%d.%s"
+		value = "first-%d"
+	}
+
+	resource "transip_acme_challenge" "second" {
+		fqdn  = "_acme-challenge.terraform-provider-transip-%d.%s"
+		value = "second-%d"
+	}
+	`, timestamp, domain, timestamp, timestamp, domain, timestamp)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_acme_challenge.first", "domain", domain),
+					resource.TestCheckResourceAttr("transip_acme_challenge.first", "name", fmt.Sprintf("_acme-challenge.terraform-provider-transip-%d", timestamp)),
+					resource.TestCheckResourceAttr("transip_acme_challenge.second", "name", fmt.Sprintf("_acme-challenge.terraform-provider-transip-%d", timestamp)),
+				),
+			},
+		},
+	})
+}
+
+func TestFindDomainForFQDN(t *testing.T) {
+	domainNames := []string{"example.com", "sub.example.com", "example.org"}
+
+	for _, c := range []struct{ fqdn, domainName, entryName string }{
+		{"_acme-challenge.www.example.com", "example.com", "_acme-challenge.www"},
+		{"_acme-challenge.sub.example.com.", "sub.example.com", "_acme-challenge"},
+		{"Example.ORG", "example.org", "@"},
+	} {
+		domainName, entryName, ok := findDomainForFQDN(domainNames, c.fqdn)
+		if !ok || domainName != c.domainName || entryName != c.entryName {
+			t.Errorf("expected %s in %s for %s, got %s in %s", c.entryName, c.domainName, c.fqdn, entryName, domainName)
+		}
+	}
+
+	if _, _, ok := findDomainForFQDN(domainNames, "_acme-challenge.notexample.com"); ok {
+		t.Error("expected no domain for name outside of the account")
+	}
+}
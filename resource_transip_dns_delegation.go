@@ -0,0 +1,430 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+// Digest type of the DS records published for a delegation (SHA-256)
+const dnsDelegationDigestType = 2
+
+func resourceDNSDelegation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDNSDelegationCreate,
+		Read:   resourceDNSDelegationRead,
+		Update: resourceDNSDelegationUpdate,
+		Delete: resourceDNSDelegationDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDNSDelegationImport,
+		},
+
+		CustomizeDiff: resourceDNSDelegationCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:             schema.TypeString,
+				Description:      "The parent domain, including the tld.",
+				Required:         true,
+				ForceNew:         true,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+				ValidateFunc:     validateDomainName,
+			},
+			"name": {
+				Type:             schema.TypeString,
+				Description:      "The name of the delegated subdomain relative to the domain, for example 'dev'.",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validation.All(validation.StringDoesNotContainAny("@*"), validateDNSEntryName),
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+			},
+			"expire": {
+				Type:        schema.TypeInt,
+				Description: "The expiration period of the NS, glue and DS entries, in seconds.",
+				Optional:    true,
+				Default:     86400,
+			},
+			"nameserver": {
+				Type:        schema.TypeList,
+				Description: "Nameservers of the delegated subdomain.",
+				Required:    true,
+				MinItems:    1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"hostname": {
+							Type:        schema.TypeString,
+							Description: "The fully qualified hostname of the nameserver.",
+							Required:    true,
+							StateFunc: func(v interface{}) string {
+								return normalizeDomainName(v.(string))
+							},
+						},
+						"ipv4": {
+							Type:         schema.TypeString,
+							Description:  "IPv4 glue address, only for nameservers inside the delegated subdomain.",
+							Optional:     true,
+							ValidateFunc: validation.IsIPv4Address,
+						},
+						"ipv6": {
+							Type:         schema.TypeString,
+							Description:  "IPv6 glue address, only for nameservers inside the delegated subdomain.",
+							Optional:     true,
+							ValidateFunc: validation.IsIPv6Address,
+						},
+					},
+				},
+			},
+			"dnskey": {
+				Type:        schema.TypeList,
+				Description: "DNSKEYs of the signed delegated subdomain, DS records are published for each of them.",
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"flags": {
+							Type:         schema.TypeInt,
+							Description:  "The key flags, either 256 (Zone Signing Key) or 257 (Key Signing Key)",
+							Optional:     true,
+							Default:      257,
+							ValidateFunc: validation.IntInSlice(dnssecFlags),
+						},
+						"algorithm": {
+							Type:         schema.TypeInt,
+							Description:  "The algorithm of the key, see: https://www.iana.org/assignments/dns-sec-alg-numbers for the possible options.",
+							Required:     true,
+							ValidateFunc: validation.IntInSlice(dnssecAlgorithms),
+						},
+						"public_key": {
+							Type:        schema.TypeString,
+							Description: "The base64 encoded public key",
+							Required:    true,
+						},
+					},
+				},
+			},
+			"ds_records": {
+				Type:        schema.TypeList,
+				Description: "Content of the DS entries published for the delegated subdomain.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceDNSDelegationImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("incorrect ID format (%q), expected: <domain>/<name>", d.Id())
+	}
+	d.SetId(fmt.Sprintf("%s/%s", normalizeDomainName(parts[0]), normalizeDomainName(parts[1])))
+	d.Set("domain", normalizeDomainName(parts[0]))
+	d.Set("name", normalizeDomainName(parts[1]))
+	d.Set("expire", 86400)
+	return []*schema.ResourceData{d}, nil
+}
+
+// Report misconfigured glue, invalid keys and domains that do not use the
+// TransIP nameservers at plan time
+func resourceDNSDelegationCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range []string{"domain", "name", "nameserver", "dnskey"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	_, err := dnsDelegationEntries(d.Get("domain").(string), d.Get("name").(string), d.Get("expire").(int), d.Get("nameserver").([]interface{}), d.Get("dnskey").([]interface{}))
+	if err != nil {
+		return err
+	}
+	return checkDNSAuthority(m, d.Get("domain").(string))
+}
+
+// The NS, glue A/AAAA and DS entries that make up a delegation
+func dnsDelegationEntries(domainName string, name string, expire int, nameservers []interface{}, dnskeys []interface{}) ([]domain.DNSEntry, error) {
+	domainName = normalizeDomainName(domainName)
+	name = normalizeDomainName(name)
+	child := name + "." + domainName
+
+	var entries []domain.DNSEntry
+	for _, v := range nameservers {
+		nameserver := v.(map[string]interface{})
+		hostname := normalizeDomainName(nameserver["hostname"].(string))
+		ipv4 := nameserver["ipv4"].(string)
+		ipv6 := nameserver["ipv6"].(string)
+
+		entries = append(entries, domain.DNSEntry{Name: name, Expire: expire, Type: "NS", Content: hostname + "."})
+
+		// Glue is only needed, and only allowed, for nameservers that can not
+		// be resolved without the delegation itself.
+		if !isInBailiwick(hostname, child) {
+			if ipv4 != "" || ipv6 != "" {
+				return nil, fmt.Errorf("nameserver %s is outside of %s and can not have glue addresses", hostname, child)
+			}
+			continue
+		}
+		if ipv4 == "" && ipv6 == "" {
+			return nil, fmt.Errorf("nameserver %s is inside of %s and requires an ipv4 or ipv6 glue address", hostname, child)
+		}
+
+		glueName := strings.TrimSuffix(hostname, "."+domainName)
+		if ipv4 != "" {
+			entries = append(entries, domain.DNSEntry{Name: glueName, Expire: expire, Type: "A", Content: ipv4})
+		}
+		if ipv6 != "" {
+			entries = append(entries, domain.DNSEntry{Name: glueName, Expire: expire, Type: "AAAA", Content: ipv6})
+		}
+	}
+
+	for i, v := range dnskeys {
+		dnskey := v.(map[string]interface{})
+		flags := dnskey["flags"].(int)
+		algorithm := dnskey["algorithm"].(int)
+		publicKey := dnskey["public_key"].(string)
+
+		keyTag, err := dnssecKeyTag(flags, algorithm, publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dnskey %d: %s", i, err)
+		}
+		digest, err := dnssecDSDigest(child, flags, algorithm, publicKey, dnsDelegationDigestType)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dnskey %d: %s", i, err)
+		}
+		entries = append(entries, domain.DNSEntry{
+			Name:    name,
+			Expire:  expire,
+			Type:    "DS",
+			Content: fmt.Sprintf("%d %d %d %s", keyTag, algorithm, dnsDelegationDigestType, digest),
+		})
+	}
+
+	return entries, nil
+}
+
+// Entries of the delegation as it is currently known in state
+func resourceDNSDelegationPreviousEntries(d *schema.ResourceData) []domain.DNSEntry {
+	if d.IsNewResource() {
+		return nil
+	}
+	expire, _ := d.GetChange("expire")
+	nameservers, _ := d.GetChange("nameserver")
+	dnskeys, _ := d.GetChange("dnskey")
+	entries, err := dnsDelegationEntries(d.Get("domain").(string), d.Get("name").(string), expire.(int), nameservers.([]interface{}), dnskeys.([]interface{}))
+	if err != nil {
+		log.Printf("[WARN] terraform-provider-transip: failed to determine previous delegation entries: %s\n", err)
+	}
+	return entries
+}
+
+func resourceDNSDelegationCreate(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+	name := d.Get("name").(string)
+
+	d.SetId(fmt.Sprintf("%s/%s", domainName, name))
+	err := resourceDNSDelegationApply(d, m)
+	if err != nil {
+		d.SetId("")
+		return err
+	}
+	return resourceDNSDelegationRead(d, m)
+}
+
+func resourceDNSDelegationUpdate(d *schema.ResourceData, m interface{}) error {
+	err := resourceDNSDelegationApply(d, m)
+	if err != nil {
+		return err
+	}
+	return resourceDNSDelegationRead(d, m)
+}
+
+// Replace the previous entries of the delegation with the configured ones.
+// All entries are changed in a single zone replacement, so the NS, glue and
+// DS entries are never published partially.
+func resourceDNSDelegationApply(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+	name := d.Get("name").(string)
+
+	entries, err := dnsDelegationEntries(domainName, name, d.Get("expire").(int), d.Get("nameserver").([]interface{}), d.Get("dnskey").([]interface{}))
+	if err != nil {
+		return err
+	}
+	previous := resourceDNSDelegationPreviousEntries(d)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		dnsDomainMutexKV.Lock(domainName)
+		defer dnsDomainMutexKV.Unlock(domainName)
+
+		dnsEntries, err := repository.GetDNSEntries(domainName)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
+		}
+
+		if d.IsNewResource() {
+			for _, e := range dnsEntries {
+				if e.Name == name && (e.Type == "NS" || e.Type == "DS") {
+					return resource.NonRetryableError(fmt.Errorf("DNS entries for %s record named %s already exist", e.Type, name))
+				}
+			}
+		}
+
+		replacement := dnsDelegationReplacement(dnsEntries, previous, entries)
+		if sameDNSEntries(replacement, dnsEntries) {
+			return nil
+		}
+
+		log.Printf("[DEBUG] terraform-provider-transip: delegating %s.%s with %v\n", name, domainName, entries)
+		err = repository.ReplaceDNSEntries(domainName, replacement)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to delegate %s for domain %s", name, domainName)
+		}
+		return nil
+	})
+}
+
+// The zone with the previous entries of the delegation replaced by the
+// configured ones, entries in both are kept.
+func dnsDelegationReplacement(dnsEntries []domain.DNSEntry, previous []domain.DNSEntry, entries []domain.DNSEntry) []domain.DNSEntry {
+	base := withoutDNSEntries(dnsEntries, previous)
+	return append(base, withoutDNSEntries(entries, base)...)
+}
+
+func resourceDNSDelegationRead(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+	name := d.Get("name").(string)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	dnsEntries, err := repository.GetDNSEntries(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to read DNS record entries for domain %s: %s", domainName, err)
+	}
+
+	var hostnames []string
+	var dsRecords []string
+	expire := d.Get("expire").(int)
+	for _, e := range dnsEntries {
+		if e.Name != name {
+			continue
+		}
+		switch e.Type {
+		case "NS":
+			hostnames = append(hostnames, strings.TrimSuffix(dnsFQDN(domainName, e.Content), "."))
+			expire = e.Expire
+		case "DS":
+			dsRecords = append(dsRecords, e.Content)
+		}
+	}
+
+	if len(hostnames) == 0 {
+		log.Printf("[DEBUG] terraform-provider-transip: delegation %s no longer exists\n", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// Keep the configured order of the nameservers that still exist
+	child := name + "." + domainName
+	existing := make(map[string]bool)
+	for _, h := range hostnames {
+		existing[h] = true
+	}
+	var sorted []string
+	for _, v := range d.Get("nameserver").([]interface{}) {
+		hostname := normalizeDomainName(v.(map[string]interface{})["hostname"].(string))
+		if existing[hostname] {
+			sorted = append(sorted, hostname)
+			delete(existing, hostname)
+		}
+	}
+	for _, h := range hostnames {
+		if existing[h] {
+			sorted = append(sorted, h)
+		}
+	}
+
+	var nameservers []map[string]interface{}
+	for _, hostname := range sorted {
+		nameserver := map[string]interface{}{"hostname": hostname, "ipv4": "", "ipv6": ""}
+		if isInBailiwick(hostname, child) {
+			glueName := strings.TrimSuffix(hostname, "."+domainName)
+			for _, e := range dnsEntries {
+				if e.Name == glueName && e.Type == "A" {
+					nameserver["ipv4"] = e.Content
+				}
+				if e.Name == glueName && e.Type == "AAAA" {
+					nameserver["ipv6"] = e.Content
+				}
+			}
+		}
+		nameservers = append(nameservers, nameserver)
+	}
+
+	// Keys can not be derived from DS entries, only keep the configured keys
+	// for which the DS entry is still published.
+	var dnskeys []interface{}
+	for _, v := range d.Get("dnskey").([]interface{}) {
+		dnskey := v.(map[string]interface{})
+		digest, err := dnssecDSDigest(child, dnskey["flags"].(int), dnskey["algorithm"].(int), dnskey["public_key"].(string), dnsDelegationDigestType)
+		if err != nil {
+			continue
+		}
+		for _, ds := range dsRecords {
+			if strings.HasSuffix(strings.ToUpper(ds), " "+digest) {
+				dnskeys = append(dnskeys, dnskey)
+				break
+			}
+		}
+	}
+
+	d.Set("expire", expire)
+	d.Set("nameserver", nameservers)
+	d.Set("dnskey", dnskeys)
+	d.Set("ds_records", dsRecords)
+	return nil
+}
+
+func resourceDNSDelegationDelete(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+	name := d.Get("name").(string)
+
+	entries, err := dnsDelegationEntries(domainName, name, d.Get("expire").(int), d.Get("nameserver").([]interface{}), d.Get("dnskey").([]interface{}))
+	if err != nil {
+		return err
+	}
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		dnsDomainMutexKV.Lock(domainName)
+		defer dnsDomainMutexKV.Unlock(domainName)
+
+		dnsEntries, err := repository.GetDNSEntries(domainName)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
+		}
+
+		// match regardless of expire, so entries whose TTL was changed elsewhere
+		// do not linger after the delegation is removed
+		log.Printf("[DEBUG] terraform-provider-transip: removing delegation of %s.%s\n", name, domainName)
+		err = repository.ReplaceDNSEntries(domainName, withoutDNSEntryContent(dnsEntries, entries))
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to remove delegation of %s for domain %s", name, domainName)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+)
+
+func TestAccTransipResourceDNSDelegation(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	timestamp := time.Now().Unix()
+	testConfig := fmt.Sprintf(`
+	resource "transip_dns_delegation" "test" {
+		domain = "%s"
+		name   = "terraform-provider-transip-%d"
+
+		nameserver {
+			hostname = "ns1.terraform-provider-transip-%d.%s"
+			ipv4     = "192.0.2.1"
+		}
+		nameserver {
+			hostname = "ns2.example.org"
+		}
+
+		dnskey {
+			algorithm  = 13
+			public_key = "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="
+		}
+	}
+	`, domain, timestamp, timestamp, domain)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_dns_delegation.test", "nameserver.#", "2"),
+					resource.TestCheckResourceAttr("transip_dns_delegation.test", "nameserver.0.ipv4", "192.0.2.1"),
+					resource.TestCheckResourceAttr("transip_dns_delegation.test", "dnskey.#", "1"),
+					resource.TestCheckResourceAttr("transip_dns_delegation.test", "ds_records.#", "1"),
+				),
+			},
+			{
+				ResourceName:            "transip_dns_delegation.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"dnskey"},
+			},
+		},
+	})
+}
+
+func TestDNSDelegationEntries(t *testing.T) {
+	nameservers := []interface{}{
+		map[string]interface{}{"hostname": "ns1.dev.example.com.", "ipv4": "192.0.2.1", "ipv6": "2001:db8::1"},
+		map[string]interface{}{"hostname": "ns.example.org", "ipv4": "", "ipv6": ""},
+	}
+	dnskeys := []interface{}{
+		// RFC 6605, section 6.1
+		map[string]interface{}{"flags": 257, "algorithm": 13, "public_key": "GojIhhXUN/u4v54ZQqGSnyhWJwaubCvTmeexv7bR6edbkrSqQpF64cYbcB7wNcP+e+MAnLr+Wi9xMWyQLc8NAA=="},
+	}
+
+	entries, err := dnsDelegationEntries("Example.com", "dev", 3600, nameservers, dnskeys)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	expected := []domain.DNSEntry{
+		{Name: "dev", Expire: 3600, Type: "NS", Content: "ns1.dev.example.com."},
+		{Name: "ns1.dev", Expire: 3600, Type: "A", Content: "192.0.2.1"},
+		{Name: "ns1.dev", Expire: 3600, Type: "AAAA", Content: "2001:db8::1"},
+		{Name: "dev", Expire: 3600, Type: "NS", Content: "ns.example.org."},
+	}
+	if len(entries) != len(expected)+1 || !reflect.DeepEqual(entries[:len(expected)], expected) {
+		t.Fatalf("expected %v followed by a DS entry, got %v", expected, entries)
+	}
+
+	// the digest itself is covered by the DNSSEC tests, it has to be of the child
+	digest, _ := dnssecDSDigest("dev.example.com", 257, 13, dnskeys[0].(map[string]interface{})["public_key"].(string), 2)
+	ds := domain.DNSEntry{Name: "dev", Expire: 3600, Type: "DS", Content: "55648 13 2 " + digest}
+	if entries[len(expected)] != ds {
+		t.Errorf("expected %v, got %v", ds, entries[len(expected)])
+	}
+
+	for _, c := range []map[string]interface{}{
+		{"hostname": "ns1.dev.example.com", "ipv4": "", "ipv6": ""},
+		{"hostname": "ns1.example.com", "ipv4": "192.0.2.1", "ipv6": ""},
+	} {
+		if _, err := dnsDelegationEntries("example.com", "dev", 3600, []interface{}{c}, nil); err == nil {
+			t.Errorf("expected error for nameserver %v", c)
+		}
+	}
+}
+
+func TestDNSDelegationReplacement(t *testing.T) {
+	ns := func(host string) domain.DNSEntry {
+		return domain.DNSEntry{Name: "dev", Expire: 86400, Type: "NS", Content: host}
+	}
+	www := domain.DNSEntry{Name: "www", Expire: 86400, Type: "A", Content: "192.0.2.1"}
+
+	tests := []struct {
+		name     string
+		zone     []domain.DNSEntry
+		previous []domain.DNSEntry
+		entries  []domain.DNSEntry
+		expected []domain.DNSEntry
+	}{
+		{
+			name:     "create",
+			zone:     []domain.DNSEntry{www},
+			entries:  []domain.DNSEntry{ns("ns1.example.net."), ns("ns2.example.net.")},
+			expected: []domain.DNSEntry{www, ns("ns1.example.net."), ns("ns2.example.net.")},
+		},
+		{
+			name:     "update one nameserver",
+			zone:     []domain.DNSEntry{www, ns("ns1.example.net."), ns("ns2.example.net.")},
+			previous: []domain.DNSEntry{ns("ns1.example.net."), ns("ns2.example.net.")},
+			entries:  []domain.DNSEntry{ns("ns1.example.net."), ns("ns3.example.net.")},
+			expected: []domain.DNSEntry{www, ns("ns1.example.net."), ns("ns3.example.net.")},
+		},
+		{
+			name:     "unchanged",
+			zone:     []domain.DNSEntry{www, ns("ns1.example.net.")},
+			previous: []domain.DNSEntry{ns("ns1.example.net.")},
+			entries:  []domain.DNSEntry{ns("ns1.example.net.")},
+			expected: []domain.DNSEntry{www, ns("ns1.example.net.")},
+		},
+		{
+			name:     "previous entries removed from the zone",
+			zone:     []domain.DNSEntry{www},
+			previous: []domain.DNSEntry{ns("ns1.example.net.")},
+			entries:  []domain.DNSEntry{ns("ns1.example.net.")},
+			expected: []domain.DNSEntry{www, ns("ns1.example.net.")},
+		},
+	}
+
+	for _, test := range tests {
+		got := dnsDelegationReplacement(test.zone, test.previous, test.entries)
+		if !sameDNSEntries(got, test.expected) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.expected, got)
+		}
+	}
+}
+
+func TestResourceDNSDelegationDelete(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "GET" && r.endpoint == "/domains/example.com/dns":
+			return `{"dnsEntries":[
+				{"name":"www","expire":86400,"type":"A","content":"192.0.2.1"},
+				{"name":"dev","expire":300,"type":"NS","content":"ns1.example.net."},
+				{"name":"dev","expire":86400,"type":"NS","content":"ns2.example.net."}
+			]}`, nil
+		case r.method == "PUT" && r.endpoint == "/domains/example.com/dns":
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceDNSDelegation().Schema, map[string]interface{}{
+		"domain": "example.com",
+		"name":   "dev",
+		"expire": 86400,
+		"nameserver": []interface{}{
+			map[string]interface{}{"hostname": "ns1.example.net"},
+			map[string]interface{}{"hostname": "ns2.example.net"},
+		},
+	})
+	d.SetId("example.com/dev")
+
+	if err := resourceDNSDelegationDelete(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	puts := client.requestsWithMethod("PUT")
+	expected := `{"dnsEntries":[{"name":"www","expire":86400,"type":"A","content":"192.0.2.1"}]}`
+	if len(puts) != 1 || puts[0].body != expected {
+		t.Errorf("expected the delegation to be removed regardless of expire, got %v", puts)
+	}
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"fmt"
 	"log"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
@@ -21,6 +23,11 @@ var errorStrings = []string{
 	"Too many requests",
 }
 
+// All DNS entry types supported by TransIP
+var dnsEntryTypes = []string{
+	"A", "AAAA", "CAA", "CNAME", "DS", "MX", "NS", "TXT", "SRV", "SSHFP", "TLSA", "ALIAS",
+}
+
 func retryableDNSRecordErrorf(err error, format string, a ...interface{}) *resource.RetryError {
 	// Check if this is a retryable error
 	isRetry := false
@@ -49,7 +56,18 @@ func resourceDNSRecord() *schema.Resource {
 		Update: resourceDNSRecordUpdate,
 		Delete: resourceDNSRecordDelete,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			State: resourceDNSRecordImport,
+		},
+
+		CustomizeDiff: resourceDNSRecordCustomizeDiff,
+
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Type:    resourceDNSRecordV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceDNSRecordStateUpgradeV0,
+				Version: 0,
+			},
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -59,17 +77,18 @@ func resourceDNSRecord() *schema.Resource {
 				Required:    true,
 				ForceNew:    true,
 				// TODO: true for transip?
-				StateFunc: func(v interface{}) string {
-					value := strings.TrimSuffix(v.(string), ".")
-					return strings.ToLower(value)
-				},
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+				ValidateFunc:     validateDomainName,
 			},
+			"unicode_domain": unicodeDomainSchema(),
 			"name": &schema.Schema{
-				Type:        schema.TypeString,
-				Description: "The name of the dns entry, for example '@' or 'www'.",
-				Required:    true,
-				// TODO: could probably be solved better, but this will do for now
-				ForceNew: true,
+				Type:             schema.TypeString,
+				Description:      "The name of the dns entry, for example '@' or 'www'.",
+				Required:         true,
+				ValidateFunc:     validateDNSEntryName,
+				StateFunc:        normalizeDNSEntryName,
+				DiffSuppressFunc: suppressDNSEntryNameDiff,
 			},
 			"expire": &schema.Schema{
 				Type:        schema.TypeInt,
@@ -78,12 +97,10 @@ func resourceDNSRecord() *schema.Resource {
 				Default:     86400,
 			},
 			"type": &schema.Schema{
-				Type:        schema.TypeString,
-				Description: "The type of dns entry. Possbible types are 'A', 'AAAA', 'CAA', 'CNAME', 'DS', 'MX', 'NS', 'TXT', 'SRV', 'SSHFP', 'TLSA' and 'ALIAS'.",
-				Required:    true,
-				ValidateFunc: validation.StringInSlice([]string{
-					"A", "AAAA", "CAA", "CNAME", "DS","MX", "NS", "TXT", "SRV", "SSHFP", "TLSA", "ALIAS",
-				}, false),
+				Type:         schema.TypeString,
+				Description:  "The type of dns entry. Possbible types are 'A', 'AAAA', 'CAA', 'CNAME', 'DS', 'MX', 'NS', 'TXT', 'SRV', 'SSHFP', 'TLSA' and 'ALIAS'.",
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(dnsEntryTypes, false),
 			},
 			"content": &schema.Schema{
 				Type:        schema.TypeSet,
@@ -93,14 +110,90 @@ func resourceDNSRecord() *schema.Resource {
 					Type: schema.TypeString,
 				},
 			},
+			"on_conflict": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "What to do when entries with this name and type already exist on creation: 'fail', 'adopt' the existing entries whose content is configured and leave the others alone, or 'overwrite' all existing entries.",
+				Optional:    true,
+				Default:     "fail",
+				ValidateFunc: validation.StringInSlice([]string{
+					"fail", "adopt", "overwrite",
+				}, false),
+			},
+			"wait_for_propagation": &schema.Schema{
+				Type:        schema.TypeBool,
+				Description: "Wait until the authoritative nameservers of the domain serve the content after the record is created or updated. Only supported for 'A', 'AAAA', 'CNAME', 'MX', 'NS', 'SRV' and 'TXT' records, other types fail the plan.",
+				Optional:    true,
+				Default:     false,
+			},
+			"propagation_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Description:  "Maximum time in seconds to wait for the record to propagate.",
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"owner_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Description: "Register ownership of the content values in TXT entries under '_terraform-owner.<name>' in the zone, so only values created by this owner are ever removed, even when the state is lost or other tools manage the same name.",
+				Optional:    true,
+				ValidateFunc: validation.StringMatch(
+					regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`),
+					"may only contain letters, digits, '_', '.' and '-'",
+				),
+			},
 		},
 	}
 }
 
+// Warn at plan time when the entries would not be served
+func resourceDNSRecordCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("wait_for_propagation").(bool) && d.NewValueKnown("type") {
+		if err := checkDNSPropagationType(d.Get("type").(string)); err != nil {
+			return err
+		}
+	}
+
+	if !d.NewValueKnown("domain") {
+		return nil
+	}
+	return checkDNSAuthority(m, d.Get("domain").(string))
+}
+
+func resourceDNSRecordImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	domainName, entryType, entryName, err := parseDNSRecordID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	dnsEntries, err := repository.GetDNSEntries(domainName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DNS record entries for domain %s: %s", domainName, err)
+	}
+
+	// An imported record takes ownership of all existing entries
+	var content []string
+	for _, e := range dnsEntries {
+		if e.Name == entryName && e.Type == entryType {
+			content = append(content, e.Content)
+		}
+	}
+	d.Set("content", content)
+	d.Set("on_conflict", "fail")
+	d.Set("wait_for_propagation", false)
+	d.Set("propagation_timeout", 300)
+
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceDNSRecordCreate(d *schema.ResourceData, m interface{}) error {
 	domainName := d.Get("domain").(string)
 	entryName := d.Get("name").(string)
 	entryType := d.Get("type").(string)
+	content := d.Get("content").(*schema.Set)
+	onConflict := d.Get("on_conflict").(string)
 
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
@@ -110,18 +203,28 @@ func resourceDNSRecordCreate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("failed to read DNS record entries for domain %s: %s", domainName, err)
 	}
 
+	var adopted []string
 	for _, e := range dnsEntries {
-		if e.Name == entryName && e.Type == entryType {
+		if e.Name != entryName || e.Type != entryType {
+			continue
+		}
+		switch onConflict {
+		case "adopt":
+			if content.Contains(e.Content) {
+				adopted = append(adopted, e.Content)
+			}
+		case "overwrite":
+			adopted = append(adopted, e.Content)
+		default:
 			return fmt.Errorf("DNS entries for %s record named %s already exist", entryType, entryName)
 		}
 	}
 
 	// Note: as soon as we use SetId, we assume the resource has been created.
 	// In this case that is not strictly true...
-	id := fmt.Sprintf("%s/%s/%s", domainName, entryType, entryName)
-	d.SetId(id)
+	d.SetId(dnsRecordID(domainName, entryType, entryName))
 
-	return resourceDNSRecordUpdate(d, m)
+	return resourceDNSRecordApply(d, m, adopted)
 }
 
 func resourceDNSRecordRead(d *schema.ResourceData, m interface{}) error {
@@ -131,19 +234,19 @@ func resourceDNSRecordRead(d *schema.ResourceData, m interface{}) error {
 	// https://github.com/transip/gotransip/blob/9defadb50daea3d11821aed85498078b9aff4986/domain/repository.go#L148
 	// don't think it would hurt omitting the expire to keep compatible with older state files for now
 	if id != "" {
-		idparts := strings.Split(id, "/")
-		if len(idparts) == 3 {
-			d.Set("domain", idparts[0])
-			d.Set("type", idparts[1])
-			d.Set("name", idparts[2])
-		} else {
-			return fmt.Errorf("Incorrect ID format, should match `domainname/type/name`")
+		domainName, entryType, entryName, err := parseDNSRecordID(id)
+		if err != nil {
+			return err
 		}
+		d.Set("domain", domainName)
+		d.Set("type", entryType)
+		d.Set("name", entryName)
 	}
 
 	domainName := d.Get("domain").(string)
 	entryName := d.Get("name").(string)
 	entryType := d.Get("type").(string)
+	d.Set("unicode_domain", domainNameToUnicode(domainName))
 
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
@@ -161,10 +264,13 @@ func resourceDNSRecordRead(d *schema.ResourceData, m interface{}) error {
 			return nil
 		}
 
+		// Only report the entries this resource manages, others are left alone
+		owned := dnsRecordOwnedContent(dnsEntries, entryName, entryType, d.Get("owner_id").(string), expandStringSet(d.Get("content").(*schema.Set)))
+
 		var content []string
 		var expire int
 		for _, e := range dnsEntries {
-			if e.Name == entryName && e.Type == entryType {
+			if e.Name == entryName && e.Type == entryType && owned[e.Content] {
 				expire = e.Expire
 				content = append(content, e.Content)
 			}
@@ -181,56 +287,62 @@ func resourceDNSRecordRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceDNSRecordUpdate(d *schema.ResourceData, m interface{}) error {
+	if !d.IsNewResource() && (d.HasChange("name") || d.HasChange("type")) {
+		return resourceDNSRecordMove(d, m)
+	}
+
+	return resourceDNSRecordApply(d, m, nil)
+}
+
+// Bring the entries of the record in line with the configuration. Only the
+// entries owned by this resource, and the adopted ones, are ever removed.
+func resourceDNSRecordApply(d *schema.ResourceData, m interface{}, adopted []string) error {
 	domainName := d.Get("domain").(string)
 
-	entryName := d.Get("name").(string)
-	expire := d.Get("expire").(int)
-	entryType := d.Get("type").(string)
-	content := d.Get("content").(*schema.Set)
+	previousContent, _ := d.GetChange("content")
+	previousOwnerID, _ := d.GetChange("owner_id")
+	record := dnsRecord{
+		Name:    d.Get("name").(string),
+		Expire:  d.Get("expire").(int),
+		Type:    d.Get("type").(string),
+		Content: expandStringSet(d.Get("content").(*schema.Set)),
+		OwnerID: d.Get("owner_id").(string),
+	}
 
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
 
-	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+	err := resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 		// We lock resources because Transip only allows one change per domain
 		// https://github.com/aequitas/terraform-provider-transip/issues/22
 		dnsDomainMutexKV.Lock(domainName)
 		defer dnsDomainMutexKV.Unlock(domainName)
 
 		// Read current entries to figure out what needs to be changed
-		log.Printf("[DEBUG] terraform-provider-transip update %s\n", entryName)
+		log.Printf("[DEBUG] terraform-provider-transip update %s\n", record.Name)
 		dnsEntries, err := repository.GetDNSEntries(domainName)
 		if err != nil {
 			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
 		}
 
-		// go through all dns entries in the zone (there is no way to read a single entry name)
-		for _, existingEntry := range dnsEntries {
-			// skip irrelevant entries (ie: the ones not being modified)
-			if existingEntry.Name != entryName || existingEntry.Type != entryType {
-				continue
-			}
+		owned := dnsRecordOwnedContent(dnsEntries, record.Name, record.Type, previousOwnerID.(string), expandStringSet(previousContent.(*schema.Set)))
+		for _, c := range adopted {
+			owned[c] = true
+		}
+
+		remove, add := dnsRecordChanges(dnsEntries, record, owned, previousOwnerID.(string))
 
-			// remove all entries for the current entry/expiry/type combination
-			log.Printf("[DEBUG] terraform-provider-transip %s removing %v\n", entryName, existingEntry)
-			err := repository.RemoveDNSEntry(domainName, existingEntry)
+		for _, dnsEntry := range remove {
+			log.Printf("[DEBUG] terraform-provider-transip %s removing %v\n", record.Name, dnsEntry)
+			err := repository.RemoveDNSEntry(domainName, dnsEntry)
 			if err != nil {
-				return retryableDNSRecordErrorf(err, "failed to remove DNS record entry for domain %s (%v)", domainName, existingEntry)
+				return retryableDNSRecordErrorf(err, "failed to remove DNS record entry for domain %s (%v)", domainName, dnsEntry)
 			}
 		}
 
-		// add all desired entries for the current entry/expiry/type combination
-		for _, c := range content.List() {
-			dnsEntry := domain.DNSEntry{
-				Name:    entryName,
-				Expire:  expire,
-				Type:    entryType,
-				Content: c.(string),
-			}
-
-			log.Printf("[DEBUG] terraform-provider-transip: %s adding %v\n", entryName, dnsEntry)
+		for _, dnsEntry := range add {
+			log.Printf("[DEBUG] terraform-provider-transip: %s adding %v\n", record.Name, dnsEntry)
 			err := repository.AddDNSEntry(domainName, dnsEntry)
-
 			if err != nil {
 				return retryableDNSRecordErrorf(err, "failed to add DNS record entry for domain %s (%v)", domainName, dnsEntry)
 			}
@@ -238,6 +350,96 @@ func resourceDNSRecordUpdate(d *schema.ResourceData, m interface{}) error {
 
 		return resource.NonRetryableError(resourceDNSRecordRead(d, m))
 	})
+	if err != nil {
+		return err
+	}
+
+	return resourceDNSRecordWaitForPropagation(d, m, record)
+}
+
+// Move the entries of a record to a new name and/or type. All entries are
+// swapped in a single zone replacement so the record never disappears from
+// the zone in between, after which the ID is updated to the new name/type.
+func resourceDNSRecordMove(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+
+	oldName, newName := d.GetChange("name")
+	oldType, newType := d.GetChange("type")
+	previousContent, _ := d.GetChange("content")
+	previousOwnerID, _ := d.GetChange("owner_id")
+	record := dnsRecord{
+		Name:    newName.(string),
+		Expire:  d.Get("expire").(int),
+		Type:    newType.(string),
+		Content: expandStringSet(d.Get("content").(*schema.Set)),
+		OwnerID: d.Get("owner_id").(string),
+	}
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	err := resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		dnsDomainMutexKV.Lock(domainName)
+		defer dnsDomainMutexKV.Unlock(domainName)
+
+		dnsEntries, err := repository.GetDNSEntries(domainName)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
+		}
+
+		for _, e := range dnsEntries {
+			if e.Name == record.Name && e.Type == record.Type {
+				return resource.NonRetryableError(fmt.Errorf("DNS entries for %s record named %s already exist", record.Type, record.Name))
+			}
+		}
+
+		// Remove the owned entries under the old name and type, and add the
+		// new ones next to the entries that are not owned by this resource.
+		owned := dnsRecordOwnedContent(dnsEntries, oldName.(string), oldType.(string), previousOwnerID.(string), expandStringSet(previousContent.(*schema.Set)))
+		oldRecord := dnsRecord{Name: oldName.(string), Type: oldType.(string)}
+		remove, _ := dnsRecordChanges(dnsEntries, oldRecord, owned, previousOwnerID.(string))
+		_, add := dnsRecordChanges(dnsEntries, record, map[string]bool{}, "")
+
+		entries := append(withoutDNSEntries(dnsEntries, remove), add...)
+
+		log.Printf("[DEBUG] terraform-provider-transip moving %s/%s to %s/%s\n", oldType, oldName, newType, newName)
+		err = repository.ReplaceDNSEntries(domainName, entries)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to move DNS record entries for domain %s", domainName)
+		}
+
+		d.SetId(dnsRecordID(domainName, record.Type, record.Name))
+
+		return resource.NonRetryableError(resourceDNSRecordRead(d, m))
+	})
+	if err != nil {
+		return err
+	}
+
+	return resourceDNSRecordWaitForPropagation(d, m, record)
+}
+
+func resourceDNSRecordWaitForPropagation(d *schema.ResourceData, m interface{}, record dnsRecord) error {
+	if !d.Get("wait_for_propagation").(bool) || len(record.Content) == 0 {
+		return nil
+	}
+
+	domainName := d.Get("domain").(string)
+	timeout := time.Duration(d.Get("propagation_timeout").(int)) * time.Second
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	nameservers, err := repository.GetNameservers(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to get nameservers of domain %q: %s", domainName, err)
+	}
+
+	err = waitForDNSPropagation(nameservers, domainName, record, timeout)
+	if err != nil {
+		return fmt.Errorf("failed waiting for %s record %s of domain %s to propagate: %s", record.Type, record.Name, domainName, err)
+	}
+	return nil
 }
 
 func resourceDNSRecordDelete(d *schema.ResourceData, m interface{}) error {
@@ -245,3 +447,71 @@ func resourceDNSRecordDelete(d *schema.ResourceData, m interface{}) error {
 
 	return resourceDNSRecordUpdate(d, m)
 }
+
+func normalizeDomainName(name string) string {
+	return labelsToASCII(strings.ToLower(strings.TrimSuffix(name, ".")))
+}
+
+func dnsRecordID(domainName string, entryType string, entryName string) string {
+	return fmt.Sprintf("%s/%s/%s", domainName, entryType, entryName)
+}
+
+func parseDNSRecordID(id string) (string, string, string, error) {
+	idparts := strings.Split(id, "/")
+	if len(idparts) != 3 {
+		return "", "", "", fmt.Errorf("Incorrect ID format, should match `domainname/type/name`")
+	}
+	return idparts[0], idparts[1], idparts[2], nil
+}
+
+// Schema of the DNS record resource before the name and type could be
+// changed in place.
+func resourceDNSRecordV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"expire": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"content": {
+				Type:     schema.TypeSet,
+				Required: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+		},
+	}
+}
+
+// Older versions never changed the ID after creation and kept the domain in
+// it exactly as it was read at create time. Now that the ID is rewritten on
+// renames it is rebuilt from its parts with the domain normalized, like the
+// domain attribute is.
+func resourceDNSRecordStateUpgradeV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	id, _ := rawState["id"].(string)
+	domainName, entryType, entryName, err := parseDNSRecordID(id)
+	if err != nil {
+		return rawState, err
+	}
+	domainName = normalizeDomainName(domainName)
+
+	rawState["id"] = dnsRecordID(domainName, entryType, entryName)
+	rawState["domain"] = domainName
+	rawState["type"] = entryType
+	rawState["name"] = entryName
+
+	return rawState, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 	"time"
 
@@ -270,6 +271,7 @@ func TestAccTransipResourceDomainRenameRecord(t *testing.T) {
 				Config: testConfig2,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("transip_dns_record.test", "content.#", "2"),
+					resource.TestCheckResourceAttr("transip_dns_record.test", "id", fmt.Sprintf("%s/A/terraform-provider-transip-changed-%d", os.Getenv("TF_VAR_domain"), timestamp)),
 				),
 			},
 		},
@@ -325,6 +327,84 @@ func TestAccTransipResourceDomainChangeType(t *testing.T) {
 				Config: testConfig2,
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttr("transip_dns_record.test", "content.#", "1"),
+					resource.TestCheckResourceAttr("transip_dns_record.test", "type", "CNAME"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceDNSRecordStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":     "Example.COM./A/www",
+		"domain": "example.com",
+		"type":   "A",
+		"name":   "www",
+	}
+	expected := map[string]interface{}{
+		"id":     "example.com/A/www",
+		"domain": "example.com",
+		"type":   "A",
+		"name":   "www",
+	}
+
+	actual, err := resourceDNSRecordStateUpgradeV0(rawState, nil)
+	if err != nil {
+		t.Fatalf("error migrating state: %s", err)
+	}
+
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("\n\nexpected:\n\n%#v\n\ngot:\n\n%#v\n\n", expected, actual)
+	}
+}
+
+func TestResourceDNSRecordStateUpgradeV0InvalidID(t *testing.T) {
+	_, err := resourceDNSRecordStateUpgradeV0(map[string]interface{}{"id": "example.com"}, nil)
+	if err == nil {
+		t.Fatal("expected error for invalid ID")
+	}
+}
+
+func TestAccTransipResourceDomainSharedOwners(t *testing.T) {
+	if v := os.Getenv("TF_VAR_domain"); v == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	timestamp := time.Now().Unix()
+	testConfig := fmt.Sprintf(`
+	terraform { required_version = ">= 0.12.0" }
+
+	data "transip_domain" "test" {
+		name = "%s"
+	}
+
+	resource "transip_dns_record" "first" {
+		domain   = data.transip_domain.test.id
+		name     = "terraform-provider-transip-owners-%d"
+		type     = "TXT"
+		content  = ["first"]
+		owner_id = "first"
+	}
+
+	resource "transip_dns_record" "second" {
+		domain      = data.transip_domain.test.id
+		name        = transip_dns_record.first.name
+		type        = "TXT"
+		content     = ["second"]
+		owner_id    = "second"
+		on_conflict = "adopt"
+	}
+	`, os.Getenv("TF_VAR_domain"), timestamp)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_dns_record.first", "content.#", "1"),
+					resource.TestCheckResourceAttr("transip_dns_record.second", "content.#", "1"),
 				),
 			},
 		},
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func resourceDNSZoneFile() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDNSZoneFileUpdate,
+		Read:   resourceDNSZoneFileRead,
+		Update: resourceDNSZoneFileUpdate,
+		Delete: resourceDNSZoneFileDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceDNSZoneFileImport,
+		},
+
+		CustomizeDiff: resourceDNSZoneFileCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:             schema.TypeString,
+				Description:      "The name, including the tld of the domain.",
+				Required:         true,
+				ForceNew:         true,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+				ValidateFunc:     validateDomainName,
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Description: "An RFC 1035 zone file with all DNS entries of the domain, replacing all existing entries. SOA records are skipped as they are managed by TransIP.",
+				Required:    true,
+			},
+			"default_ttl": {
+				Type:         schema.TypeInt,
+				Description:  "The expiration period in seconds for records without TTL when the zone file has no $TTL.",
+				Optional:     true,
+				Default:      86400,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+		},
+	}
+}
+
+func resourceDNSZoneFileImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("domain", d.Id())
+	d.Set("default_ttl", 86400)
+	return []*schema.ResourceData{d}, nil
+}
+
+// Report zone file errors, like unsupported record types, and domains that
+// do not use the TransIP nameservers at plan time
+func resourceDNSZoneFileCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("domain") || !d.NewValueKnown("content") {
+		return nil
+	}
+	_, err := parseZoneFile(d.Get("domain").(string), d.Get("content").(string), d.Get("default_ttl").(int))
+	if err != nil {
+		return fmt.Errorf("invalid zone file: %s", err)
+	}
+	return checkDNSAuthority(m, d.Get("domain").(string))
+}
+
+func resourceDNSZoneFileRead(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	dnsEntries, err := repository.GetDNSEntries(domainName)
+	if err != nil {
+		return fmt.Errorf("failed to read DNS record entries for domain %s: %s", domainName, err)
+	}
+
+	// Keep the zone file as written as long as it describes the same entries
+	entries, err := parseZoneFile(domainName, d.Get("content").(string), d.Get("default_ttl").(int))
+	if err != nil || !sameDNSEntries(entries, dnsEntries) {
+		log.Printf("[DEBUG] terraform-provider-transip: DNS entries of domain %s differ from zone file\n", domainName)
+		d.Set("content", renderZoneFile(domainName, dnsEntries))
+	}
+
+	d.SetId(domainName)
+	return nil
+}
+
+func resourceDNSZoneFileUpdate(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+
+	entries, err := parseZoneFile(domainName, d.Get("content").(string), d.Get("default_ttl").(int))
+	if err != nil {
+		return fmt.Errorf("invalid zone file: %s", err)
+	}
+
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	}
+
+	err = replaceDNSEntries(m, domainName, timeout, func([]domain.DNSEntry) []domain.DNSEntry {
+		return entries
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(domainName)
+	return resourceDNSZoneFileRead(d, m)
+}
+
+// Only the entries of the zone file are removed, entries added since the last
+// refresh are kept.
+func resourceDNSZoneFileDelete(d *schema.ResourceData, m interface{}) error {
+	domainName := d.Get("domain").(string)
+
+	entries, err := parseZoneFile(domainName, d.Get("content").(string), d.Get("default_ttl").(int))
+	if err != nil {
+		return fmt.Errorf("invalid zone file: %s", err)
+	}
+
+	return replaceDNSEntries(m, domainName, d.Timeout(schema.TimeoutDelete), func(dnsEntries []domain.DNSEntry) []domain.DNSEntry {
+		return withoutDNSEntryContent(dnsEntries, entries)
+	})
+}
+
+// Replace the DNS entries of the domain with the entries returned by replace,
+// which is given the current entries, in a single locked zone edit.
+func replaceDNSEntries(m interface{}, domainName string, timeout time.Duration, replace func([]domain.DNSEntry) []domain.DNSEntry) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		dnsDomainMutexKV.Lock(domainName)
+		defer dnsDomainMutexKV.Unlock(domainName)
+
+		dnsEntries, err := repository.GetDNSEntries(domainName)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to get existing DNS record entries for domain %s", domainName)
+		}
+
+		entries := replace(dnsEntries)
+		log.Printf("[DEBUG] terraform-provider-transip: replacing DNS entries of domain %s with %v\n", domainName, entries)
+		err = repository.ReplaceDNSEntries(domainName, entries)
+		if err != nil {
+			return retryableDNSRecordErrorf(err, "failed to replace DNS record entries for domain %s", domainName)
+		}
+		return nil
+	})
+}
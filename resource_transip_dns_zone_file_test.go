@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func TestAccTransipResourceDNSZoneFileUnsupportedType(t *testing.T) {
+	testConfig := `
+	resource "transip_dns_zone_file" "test" {
+		domain  = "example.com"
+		content = "@ IN PTR host.example.com."
+	}
+	`
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      testConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("record type PTR is not supported by TransIP"),
+			},
+		},
+	})
+}
+
+func TestAccTransipResourceDNSZoneFile(t *testing.T) {
+	if os.Getenv("TF_VAR_zone_file_domain") == "" {
+		t.Skip("TF_VAR_zone_file_domain must be set to a domain whose DNS entries may be replaced")
+	}
+
+	testConfig := fmt.Sprintf(`
+	resource "transip_dns_zone_file" "test" {
+		domain  = "%s"
+		content = <<-EOT
+			$TTL 300
+			@    IN A     192.0.2.1
+			www  IN CNAME @
+			@    IN TXT   "terraform-provider-transip"
+		EOT
+	}
+	`, os.Getenv("TF_VAR_zone_file_domain"))
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_dns_zone_file.test", "id", os.Getenv("TF_VAR_zone_file_domain")),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceDNSZoneFileDelete(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "GET" && r.endpoint == "/domains/example.com/dns":
+			return `{"dnsEntries":[
+				{"name":"@","expire":3600,"type":"A","content":"192.0.2.1"},
+				{"name":"www","expire":60,"type":"CNAME","content":"@"},
+				{"name":"mail","expire":3600,"type":"A","content":"192.0.2.2"}
+			]}`, nil
+		case r.method == "PUT" && r.endpoint == "/domains/example.com/dns":
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceDNSZoneFile().Schema, map[string]interface{}{
+		"domain":  "example.com",
+		"content": "@ 3600 IN A 192.0.2.1\nwww 3600 IN CNAME @\n",
+	})
+	d.SetId("example.com")
+
+	if err := resourceDNSZoneFileDelete(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	puts := client.requestsWithMethod("PUT")
+	expected := `{"dnsEntries":[{"name":"mail","expire":3600,"type":"A","content":"192.0.2.2"}]}`
+	if len(puts) != 1 || puts[0].body != expected {
+		t.Errorf("expected only the entries of the zone file to be removed, got %v", puts)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
@@ -15,35 +16,150 @@ func resourceDomain() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceDomainCreate,
 		Read:   resourceDomainRead,
-		// Update: resourceDomainUpdate,
+		Update: resourceDomainUpdate,
 		Delete: resourceDomainDelete,
 
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		CustomizeDiff: resourceDomainCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
+				Type:             schema.TypeString,
+				Description:      "The name, including the tld of this domain",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateDomainName,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+			},
+			"unicode_name": {
+				Type:        schema.TypeString,
+				Description: "The name of this domain in unicode, for internationalized domain names",
+				Computed:    true,
+			},
+			"contact": {
+				Type:        schema.TypeList,
+				Description: "WHOIS contacts to register the domain with, instead of the account defaults",
+				Optional:    true,
+				Elem:        whoisContactSchema(),
+			},
+			"nameserver": {
+				Type:        schema.TypeList,
+				Description: "Nameservers to register the domain with, instead of the TransIP nameservers",
+				Optional:    true,
+				Elem:        nameserverSchema(),
+			},
+			"dns_entry": {
+				Type:        schema.TypeList,
+				Description: "DNS entries to register the domain with, next to the TransIP default entries",
+				Optional:    true,
+				Elem:        dnsEntrySchema(),
+			},
+			"action_retries": domainActionRetriesSchema(),
+			"purge_default_dns_entries": {
+				Type:        schema.TypeBool,
+				Description: "Replace the TransIP default DNS entries with only the entries of `dns_entry` once the domain is active",
+				Optional:    true,
+				Default:     false,
+			},
+			"is_transfer_locked": {
+				Type:        schema.TypeBool,
+				Description: "Lock the ability to transfer the domain at the registry, if the domain supports transfer locking",
+				Optional:    true,
+				Computed:    true,
+			},
+			"is_whitelabel": {
+				Type:        schema.TypeBool,
+				Description: "Add the domain to your whitelabel, this can not be undone",
+				Optional:    true,
+				Computed:    true,
+			},
+			"tags": {
+				Type:        schema.TypeSet,
+				Description: "The custom tags added to this domain",
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"cancellation_time":   cancellationTimeSchema(),
+			"deletion_protection": deletionProtectionSchema(),
+			"cancellation_status": {
+				Type:        schema.TypeString,
+				Description: "Cancellation status, empty if the domain is active, 'cancelled' when the domain is cancelled or pending cancellation",
+				Computed:    true,
+			},
+			"cancellation_date": {
+				Type:        schema.TypeString,
+				Description: "Date the domain is cancelled, empty if the domain is active",
+				Computed:    true,
+			},
+			"failed_action": {
+				Type:        schema.TypeString,
+				Description: "The name of the domain action that failed at the registry, empty when no action failed",
+				Computed:    true,
+			},
+			"failed_action_message": {
 				Type:        schema.TypeString,
-				Description: "The name, including the tld of this domain",
-				Required:    true,
-				// TODO: implement update
-				ForceNew: true,
+				Description: "The message of the registry for the failed domain action",
+				Computed:    true,
+			},
+			"auto_renew": {
+				Type:        schema.TypeBool,
+				Description: "Renew the domain at the end of its term, disabling it cancels the domain at the end of its term which can not be enabled again through the API",
+				Optional:    true,
+				Computed:    true,
 			},
 		},
 	}
 }
 
+// Validate the name against the constraints of its TLD before registering it,
+// and reject changes to the registration arguments afterwards
+func resourceDomainCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if err := checkDomainAutoRenew(d); err != nil {
+		return err
+	}
+	if d.Id() != "" {
+		return checkDomainRegistrationChanges(d)
+	}
+	if !d.NewValueKnown("name") {
+		return nil
+	}
+
+	client, ok := m.(repository.Client)
+	if !ok {
+		return nil
+	}
+	repository := domain.Repository{Client: client}
+
+	name := d.Get("name").(string)
+	tld, err := getDomainTLD(repository, name)
+	if err != nil {
+		return err
+	}
+	return validateDomainNameForTLD(name, tld)
+}
+
 func resourceDomainCreate(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("name").(string)
 
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
 
-	register := domain.Register{
-		DomainName: name,
+	register := domainOrder{
+		DomainName:  name,
+		Contacts:    interfacesToWhoisContacts(d.Get("contact").([]interface{})),
+		DNSEntries:  interfacesToDNSEntries(d.Get("dns_entry").([]interface{})),
+		Nameservers: interfacesToNameservers(d.Get("nameserver").([]interface{})),
 	}
-	err := repository.Register(register)
+	err := postDomainOrder(client, register)
 	if err != nil {
 		return fmt.Errorf("failed to register domain %q: %s", name, err)
 	}
@@ -62,7 +178,22 @@ func resourceDomainCreate(d *schema.ResourceData, m interface{}) error {
 
 	d.SetId(name)
 
-	return resourceDomainRead(d, m)
+	err = waitForDomainAction(client, name, d.Timeout(schema.TimeoutCreate), d.Get("action_retries").(int), register)
+	if err != nil {
+		return fmt.Errorf("Error waiting for registration of domain to complete: %s", err)
+	}
+
+	if d.Get("purge_default_dns_entries").(bool) {
+		log.Printf("[DEBUG] terraform-provider-transip: purging default DNS entries of domain %s\n", name)
+		err = replaceDNSEntries(m, name, d.Timeout(schema.TimeoutCreate), func([]domain.DNSEntry) []domain.DNSEntry {
+			return register.DNSEntries
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceDomainUpdate(d, m)
 }
 
 func resourceDomainRead(d *schema.ResourceData, m interface{}) error {
@@ -78,14 +209,82 @@ func resourceDomainRead(d *schema.ResourceData, m interface{}) error {
 
 	d.SetId(i.Name)
 
+	action, err := getDomainAction(repository, name)
+	if err != nil {
+		return err
+	}
+	if action != nil && action.HasFailed {
+		log.Printf("[WARN] terraform-provider-transip: domain action %q of domain %s failed: %s\n", action.Name, name, action.Message)
+		d.Set("failed_action", action.Name)
+		d.Set("failed_action_message", action.Message)
+	} else {
+		d.Set("failed_action", "")
+		d.Set("failed_action_message", "")
+	}
+
 	d.Set("name", name)
+	d.Set("unicode_name", domainNameToUnicode(name))
+	d.Set("is_transfer_locked", i.IsTransferLocked)
+	d.Set("is_whitelabel", i.IsWhitelabel)
+	d.Set("tags", i.Tags)
+	d.Set("auto_renew", i.CancellationStatus == "")
+	d.Set("cancellation_status", i.CancellationStatus)
+	if i.CancellationDate.Time.IsZero() {
+		d.Set("cancellation_date", "")
+	} else {
+		d.Set("cancellation_date", i.CancellationDate.Time.Format("2006-01-02 15:04:05"))
+	}
 
 	return nil
 }
 
-// func resourceDomainUpdate(d *schema.ResourceData, m interface{}) error {
-// 	return resourceDomainRead(d, m)
-// }
+func resourceDomainUpdate(d *schema.ResourceData, m interface{}) error {
+	name := d.Id()
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	if d.HasChanges("is_transfer_locked", "is_whitelabel", "tags") {
+		i, err := repository.GetByDomainName(name)
+		if err != nil {
+			return fmt.Errorf("failed to lookup domain %q: %s", name, err)
+		}
+
+		if old, new := d.GetChange("is_whitelabel"); old.(bool) && !new.(bool) {
+			return fmt.Errorf("domain %q can not be removed from the whitelabel", name)
+		}
+
+		i.IsTransferLocked = d.Get("is_transfer_locked").(bool)
+		i.IsWhitelabel = d.Get("is_whitelabel").(bool)
+		i.Tags = expandStringSet(d.Get("tags").(*schema.Set))
+
+		err = repository.Update(i)
+		if err != nil {
+			return fmt.Errorf("failed to update domain %q: %s", name, err)
+		}
+	}
+
+	// TransIP renews domains until they are cancelled, so disabling auto renew
+	// cancels the domain at the end of its term. An unset auto_renew reads as
+	// false on create, so only an explicit false cancels a new domain.
+	autoRenew, ok := d.GetOkExists("auto_renew")
+	if !d.IsNewResource() {
+		ok = d.HasChange("auto_renew")
+	}
+	if ok && !autoRenew.(bool) {
+		if d.Get("deletion_protection").(bool) {
+			return fmt.Errorf("domain %q has deletion_protection enabled, disable it before disabling auto_renew", name)
+		}
+		err := repository.Cancel(name, gotransip.CancellationTimeEnd)
+		if err != nil {
+			return fmt.Errorf("failed to cancel domain %q at the end of its term: %s", name, err)
+		}
+	} else if ok && !d.IsNewResource() {
+		return fmt.Errorf("domain %q is cancelled at the end of its term, this can not be undone through the API", name)
+	}
+
+	return resourceDomainRead(d, m)
+}
 
 func resourceDomainDelete(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("name").(string)
@@ -93,7 +292,12 @@ func resourceDomainDelete(d *schema.ResourceData, m interface{}) error {
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
 
-	err := repository.Cancel(name, gotransip.CancellationTimeImmediately)
+	err := checkDeletionProtection(d, "domain", name)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Cancel(name, cancellationTime(d, "domain", name))
 	if err != nil {
 		return fmt.Errorf("failed to cancel domain %q: %s", name, err)
 	}
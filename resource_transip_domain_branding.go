@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func resourceDomainBranding() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDomainBrandingUpdate,
+		Read:   resourceDomainBrandingRead,
+		Update: resourceDomainBrandingUpdate,
+		Delete: resourceDomainBrandingDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceDomainBrandingImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:             schema.TypeString,
+				Description:      "The domain, including the tld",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateDomainName,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+			},
+			"unicode_domain": unicodeDomainSchema(),
+			"company_name": {
+				Type:        schema.TypeString,
+				Description: "The company name displayed in transfer-branded e-mails",
+				Optional:    true,
+			},
+			"company_url": {
+				Type:        schema.TypeString,
+				Description: "The company url displayed in transfer-branded e-mails",
+				Optional:    true,
+			},
+			"support_email": {
+				Type:        schema.TypeString,
+				Description: "The support email used for transfer-branded e-mails",
+				Optional:    true,
+			},
+			"terms_of_usage_url": {
+				Type:        schema.TypeString,
+				Description: "The terms of usage url as displayed in transfer-branded e-mails",
+				Optional:    true,
+			},
+			"banner_lines": {
+				Type:        schema.TypeList,
+				Description: "Up to three generic banner lines displayed in whois-branded whois output",
+				Optional:    true,
+				MaxItems:    3,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceDomainBrandingImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("domain", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceDomainBrandingRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	domain := d.Get("domain").(string)
+	branding, err := repository.GetBranding(domain)
+	if err != nil {
+		return fmt.Errorf("failed to get branding of domain %q: %s", domain, err)
+	}
+
+	// trailing empty banner lines are not configured
+	bannerLines := []string{branding.BannerLine1, branding.BannerLine2, branding.BannerLine3}
+	for len(bannerLines) > 0 && bannerLines[len(bannerLines)-1] == "" {
+		bannerLines = bannerLines[:len(bannerLines)-1]
+	}
+
+	d.Set("company_name", branding.CompanyName)
+	d.Set("company_url", branding.CompanyURL)
+	d.Set("support_email", branding.SupportEmail)
+	d.Set("terms_of_usage_url", branding.TermsOfUsageURL)
+	d.Set("banner_lines", bannerLines)
+
+	d.Set("unicode_domain", domainNameToUnicode(domain))
+	d.SetId(domain)
+	return nil
+}
+
+func resourceDomainBrandingUpdate(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	bannerLines := make([]string, 3)
+	for i, line := range d.Get("banner_lines").([]interface{}) {
+		if line != nil {
+			bannerLines[i] = line.(string)
+		}
+	}
+
+	domainName := d.Get("domain").(string)
+	branding := domain.Branding{
+		BannerLine1:     bannerLines[0],
+		BannerLine2:     bannerLines[1],
+		BannerLine3:     bannerLines[2],
+		CompanyName:     d.Get("company_name").(string),
+		CompanyURL:      d.Get("company_url").(string),
+		SupportEmail:    d.Get("support_email").(string),
+		TermsOfUsageURL: d.Get("terms_of_usage_url").(string),
+	}
+	err := repository.UpdateBranding(domainName, branding)
+	if err != nil {
+		return fmt.Errorf("failed to update branding of domain %q: %s", domainName, err)
+	}
+
+	d.SetId(domainName)
+	return resourceDomainBrandingRead(d, m)
+}
+
+// Clearing the branding makes the account defaults apply again
+func resourceDomainBrandingDelete(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	domainName := d.Get("domain").(string)
+	err := repository.UpdateBranding(domainName, domain.Branding{})
+	if err != nil {
+		return fmt.Errorf("failed to reset branding of domain %q: %s", domainName, err)
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestAccTransipResourceDomainBranding(t *testing.T) {
+	domain := os.Getenv("TF_VAR_domain")
+	if domain == "" {
+		t.Skip("TF_VAR_domain must be set for acceptance tests")
+	}
+
+	testConfig := fmt.Sprintf(`
+	resource "transip_domain_branding" "test" {
+		domain        = "%s"
+		company_name  = "terraform-provider-transip"
+		company_url   = "https://github.com/aequitas/terraform-provider-transip"
+		support_email = "support@example.com"
+		banner_lines  = ["Managed by terraform-provider-transip"]
+	}
+	`, domain)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_domain_branding.test", "company_name", "terraform-provider-transip"),
+					resource.TestCheckResourceAttr("transip_domain_branding.test", "banner_lines.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "transip_domain_branding.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func resourceDomainContacts() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDomainContactsUpdate,
+		Read:   resourceDomainContactsRead,
+		Update: resourceDomainContactsUpdate,
+		Delete: resourceDomainContactsDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceDomainContactsImport,
+		},
+
+		CustomizeDiff: resourceDomainContactsCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:             schema.TypeString,
+				Description:      "The domain, including the tld",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateDomainName,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+			},
+			"unicode_domain": unicodeDomainSchema(),
+			"contact": {
+				Type:        schema.TypeSet,
+				Description: "The WHOIS contacts of the domain, one of each type",
+				Required:    true,
+				Elem:        whoisContactSchema(),
+			},
+		},
+	}
+}
+
+func resourceDomainContactsImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("domain", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+// Every contact type is required exactly once, company details are required
+// together.
+func resourceDomainContactsCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("contact") {
+		return nil
+	}
+
+	contacts := interfacesToWhoisContacts(d.Get("contact").(*schema.Set).List())
+	return validateWhoisContacts(contacts)
+}
+
+func validateWhoisContacts(contacts []domain.WhoisContact) error {
+	count := make(map[string]int)
+	for _, contact := range contacts {
+		count[contact.Type]++
+		if contact.CompanyName != "" && contact.CompanyType == "" {
+			return fmt.Errorf("%s contact with company_name requires a company_type", contact.Type)
+		}
+		if contact.CompanyName == "" && (contact.CompanyType != "" || contact.CompanyKvk != "") {
+			return fmt.Errorf("%s contact with company_type or company_kvk requires a company_name", contact.Type)
+		}
+	}
+	for _, contactType := range whoisContactTypes {
+		if count[contactType] != 1 {
+			return fmt.Errorf("exactly one %s contact is required, got %d", contactType, count[contactType])
+		}
+	}
+	return nil
+}
+
+func resourceDomainContactsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	domain := d.Get("domain").(string)
+	contacts, err := repository.GetContacts(domain)
+	if err != nil {
+		return fmt.Errorf("failed to get contacts of domain %q: %s", domain, err)
+	}
+	err = d.Set("contact", whoisContactsToMaps(contacts))
+	if err != nil {
+		return fmt.Errorf("failed to parse contacts of domain %q: %s", domain, err)
+	}
+
+	d.Set("unicode_domain", domainNameToUnicode(domain))
+	d.SetId(domain)
+	return nil
+}
+
+func resourceDomainContactsUpdate(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	domain := d.Get("domain").(string)
+	contacts := interfacesToWhoisContacts(d.Get("contact").(*schema.Set).List())
+	err := repository.UpdateContacts(domain, contacts)
+	if err != nil {
+		return fmt.Errorf("failed to update contacts of domain %q: %s", domain, err)
+	}
+
+	d.SetId(domain)
+	return resourceDomainContactsRead(d, m)
+}
+
+// A domain can not be without contacts, so they are left as they are.
+func resourceDomainContactsDelete(d *schema.ResourceData, m interface{}) error {
+	log.Printf("[DEBUG] terraform-provider-transip: contacts of domain %s are kept as they are\n", d.Id())
+	d.SetId("")
+	return nil
+}
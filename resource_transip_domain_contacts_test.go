@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/transip/gotransip/v6/domain"
+)
+
+func TestValidateWhoisContacts(t *testing.T) {
+	contact := domain.WhoisContact{FirstName: "John", LastName: "Doe", Email: "john@example.com", Country: "nl"}
+	withType := func(contactType string, c domain.WhoisContact) domain.WhoisContact {
+		c.Type = contactType
+		return c
+	}
+	company := contact
+	company.CompanyName = "Example"
+	company.CompanyType = "BV"
+
+	valid := []domain.WhoisContact{withType("registrant", company), withType("administrative", contact), withType("technical", contact)}
+	if err := validateWhoisContacts(valid); err != nil {
+		t.Errorf("expected contacts to be valid: %s", err)
+	}
+
+	missingCompanyType := company
+	missingCompanyType.CompanyType = ""
+	missingCompanyName := contact
+	missingCompanyName.CompanyKvk = "12345678"
+
+	for _, contacts := range [][]domain.WhoisContact{
+		{withType("registrant", contact), withType("technical", contact)},
+		{withType("registrant", contact), withType("registrant", contact), withType("administrative", contact), withType("technical", contact)},
+		{withType("registrant", missingCompanyType), withType("administrative", contact), withType("technical", contact)},
+		{withType("registrant", missingCompanyName), withType("administrative", contact), withType("technical", contact)},
+	} {
+		if err := validateWhoisContacts(contacts); err == nil {
+			t.Errorf("expected error for contacts %v", contacts)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/transip/gotransip/v6/domain"
 	"github.com/transip/gotransip/v6/repository"
 )
@@ -19,12 +22,18 @@ func resourceDomainDNSSec() *schema.Resource {
 			State: resourceDomainDNSSecImport,
 		},
 
+		CustomizeDiff: resourceDomainDNSSecCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"domain": {
-				Type:        schema.TypeString,
-				Description: "The domain, including the tld",
-				Required:    true,
+				Type:             schema.TypeString,
+				Description:      "The domain, including the tld",
+				Required:         true,
+				ValidateFunc:     validateDomainName,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
 			},
+			"unicode_domain": unicodeDomainSchema(),
 			"dnssec": {
 				Type:        schema.TypeList,
 				Description: "List of dnssec entries associated with domain",
@@ -32,19 +41,22 @@ func resourceDomainDNSSec() *schema.Resource {
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"key_tag": {
-							Type:        schema.TypeInt,
-							Description: "A 5-digit key of the Zonesigner",
-							Required:    true,
+							Type:             schema.TypeInt,
+							Description:      "A 5-digit key of the Zonesigner, computed from the public key when omitted",
+							Optional:         true,
+							DiffSuppressFunc: suppressComputedKeyTag,
 						},
 						"flags": {
-							Type:        schema.TypeInt,
-							Description: "The signing key number, either 256 (Zone Signing Key) or 257 (Key Signing Key)",
-							Required:    true,
+							Type:         schema.TypeInt,
+							Description:  "The signing key number, either 256 (Zone Signing Key) or 257 (Key Signing Key)",
+							Required:     true,
+							ValidateFunc: validation.IntInSlice(dnssecFlags),
 						},
 						"algorithm": {
-							Type:        schema.TypeInt,
-							Description: "The algorithm type that is used, see: https://www.transip.nl/vragen/461-domeinnaam-nameservers-gebruikt-beveiligen-dnssec/ for the possible options.",
-							Required:    true,
+							Type:         schema.TypeInt,
+							Description:  "The algorithm type that is used, see: https://www.iana.org/assignments/dns-sec-alg-numbers for the possible options. The deprecated algorithms 3, 5, 6, 7 and 12 are not accepted.",
+							Required:     true,
+							ValidateFunc: validation.IntInSlice(dnssecAlgorithms),
 						},
 						"public_key": {
 							Type:        schema.TypeString,
@@ -54,10 +66,85 @@ func resourceDomainDNSSec() *schema.Resource {
 					},
 				},
 			},
+			"ds_records": {
+				Type:        schema.TypeList,
+				Description: "DS records with SHA-256 and SHA-384 digests of the key signing keys (flags 257), to publish at the parent zone",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"key_tag": {
+							Type:        schema.TypeInt,
+							Description: "The key tag of the DNSKEY",
+							Computed:    true,
+						},
+						"algorithm": {
+							Type:        schema.TypeInt,
+							Description: "The algorithm of the DNSKEY",
+							Computed:    true,
+						},
+						"digest_type": {
+							Type:        schema.TypeInt,
+							Description: "The digest type, either 2 (SHA-256) or 4 (SHA-384)",
+							Computed:    true,
+						},
+						"digest": {
+							Type:        schema.TypeString,
+							Description: "The hex encoded digest of the DNSKEY",
+							Computed:    true,
+						},
+						"record": {
+							Type:        schema.TypeString,
+							Description: "The content of the DS record, for example '12345 13 2 ABCDEF...'",
+							Computed:    true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
+// An omitted key tag is computed from the key, so it only differs when the
+// key tag in state is not the one of the configured key.
+func suppressComputedKeyTag(k, old, new string, d *schema.ResourceData) bool {
+	if new != "0" && new != "" {
+		return false
+	}
+	prefix := strings.TrimSuffix(k, "key_tag")
+	keyTag, err := dnssecKeyTag(d.Get(prefix+"flags").(int), d.Get(prefix+"algorithm").(int), d.Get(prefix+"public_key").(string))
+	return err == nil && old == strconv.Itoa(keyTag)
+}
+
+// Validate the configured keys and compute the DS records at plan time
+func resourceDomainDNSSecCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("dnssec") || !d.NewValueKnown("domain") {
+		return d.SetNewComputed("ds_records")
+	}
+
+	domain := d.Get("domain").(string)
+	var dsRecords []map[string]interface{}
+	for i, entry := range interfacesToDNSSec(d.Get("dnssec").([]interface{})) {
+		keyTag, err := dnssecKeyTag(entry.Flags, entry.Algorithm, entry.PublicKey)
+		if err != nil {
+			return fmt.Errorf("invalid dnssec entry %d: %s", i, err)
+		}
+		if entry.KeyTag != 0 && entry.KeyTag != keyTag {
+			return fmt.Errorf("invalid dnssec entry %d: key_tag %d does not match key tag %d of the public key", i, entry.KeyTag, keyTag)
+		}
+
+		records, err := dnssecDSRecords(domain, entry.Flags, entry.Algorithm, entry.PublicKey)
+		if err != nil {
+			return fmt.Errorf("invalid dnssec entry %d: %s", i, err)
+		}
+		dsRecords = append(dsRecords, records...)
+	}
+
+	if !d.HasChange("dnssec") && !d.HasChange("domain") {
+		return nil
+	}
+	return d.SetNew("ds_records", dsRecords)
+}
+
 func resourceDomainDNSSecImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	d.Set("domain", d.Id())
 	return []*schema.ResourceData{d}, nil
@@ -77,6 +164,17 @@ func resourceDomainDNSSecRead(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("failed to parse dnssec entries of domain %q: %s", domain, err)
 	}
 
+	var dsRecords []map[string]interface{}
+	for _, entry := range entries {
+		records, err := dnssecDSRecords(domain, entry.Flags, entry.Algorithm, entry.PublicKey)
+		if err != nil {
+			return fmt.Errorf("failed to compute DS records of domain %q: %s", domain, err)
+		}
+		dsRecords = append(dsRecords, records...)
+	}
+	d.Set("ds_records", dsRecords)
+
+	d.Set("unicode_domain", domainNameToUnicode(domain))
 	d.SetId(domain)
 	return nil
 }
@@ -87,13 +185,24 @@ func resourceDomainDNSSecUpdate(d *schema.ResourceData, m interface{}) error {
 
 	domain := d.Get("domain").(string)
 	entries := interfacesToDNSSec(d.Get("dnssec").([]interface{}))
+	for i, entry := range entries {
+		if entry.KeyTag != 0 {
+			continue
+		}
+		keyTag, err := dnssecKeyTag(entry.Flags, entry.Algorithm, entry.PublicKey)
+		if err != nil {
+			return fmt.Errorf("failed to compute key tag for dnssec entry of domain %q: %s", domain, err)
+		}
+		entries[i].KeyTag = keyTag
+	}
+
 	err := repository.ReplaceDNSSecEntries(domain, entries)
 	if err != nil {
 		return fmt.Errorf("failed to update dnssec entries of domain %q: %s", domain, err)
 	}
 
 	d.SetId(domain)
-	return nil
+	return resourceDomainDNSSecRead(d, m)
 }
 
 func resourceDomainDNSSecDelete(d *schema.ResourceData, m interface{}) error {
@@ -3,15 +3,18 @@ package main
 import (
 	"fmt"
 	"net"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 	"github.com/transip/gotransip/v6/domain"
 	"github.com/transip/gotransip/v6/repository"
 )
 
 func resourceDomainNameservers() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceDomainNameserversUpdate,
+		Create: resourceDomainNameserversCreate,
 		Read:   resourceDomainNameserversRead,
 		Update: resourceDomainNameserversUpdate,
 		Delete: resourceDomainNameserversDelete,
@@ -20,12 +23,24 @@ func resourceDomainNameservers() *schema.Resource {
 			State: resourceDomainNameserversImport,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		CustomizeDiff: resourceDomainNameserversCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"domain": {
-				Type:        schema.TypeString,
-				Description: "The domain, including the tld",
-				Required:    true,
+				Type:             schema.TypeString,
+				Description:      "The domain, including the tld",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateDomainName,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
 			},
+			"unicode_domain": unicodeDomainSchema(),
 			"nameserver": {
 				Type:        schema.TypeList,
 				Description: "List of nameservers associated with domain",
@@ -38,14 +53,44 @@ func resourceDomainNameservers() *schema.Resource {
 							Required:    true,
 						},
 						"ipv4": {
-							Type:        schema.TypeString,
-							Description: "Optional ipv4 glue record for this nameserver",
-							Optional:    true,
+							Type:         schema.TypeString,
+							Description:  "Optional ipv4 glue record for this nameserver, only for hostnames inside the domain",
+							Optional:     true,
+							ValidateFunc: validation.IsIPv4Address,
 						},
 						"ipv6": {
-							Type:        schema.TypeString,
-							Description: "Optional ipv6 glue record for this nameserver",
-							Optional:    true,
+							Type:         schema.TypeString,
+							Description:  "Optional ipv6 glue record for this nameserver, only for hostnames inside the domain",
+							Optional:     true,
+							ValidateFunc: validation.IsIPv6Address,
+						},
+					},
+				},
+			},
+			"action_retries": domainActionRetriesSchema(),
+			"on_destroy_nameservers": {
+				Type:        schema.TypeList,
+				Description: "Hostnames of the nameservers to set when this resource is destroyed, instead of the nameservers the domain had before it was created",
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"previous_nameserver": {
+				Type:        schema.TypeList,
+				Description: "The nameservers of the domain before this resource was created, restored when it is destroyed",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ipv4": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ipv6": {
+							Type:     schema.TypeString,
+							Computed: true,
 						},
 					},
 				},
@@ -54,6 +99,30 @@ func resourceDomainNameservers() *schema.Resource {
 	}
 }
 
+// Glue records are only allowed for nameservers inside the domain itself
+func resourceDomainNameserversCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("domain") || !d.NewValueKnown("nameserver") {
+		return nil
+	}
+
+	domainName := d.Get("domain").(string)
+	for _, v := range d.Get("nameserver").([]interface{}) {
+		nameserver := v.(map[string]interface{})
+		hostname := nameserver["hostname"].(string)
+		if (nameserver["ipv4"] != "" || nameserver["ipv6"] != "") && !isInBailiwick(hostname, domainName) {
+			return fmt.Errorf("nameserver %s is outside of domain %s and can not have glue records", hostname, domainName)
+		}
+	}
+	return nil
+}
+
+// Whether the hostname is the domain itself or one of its subdomains
+func isInBailiwick(hostname string, domainName string) bool {
+	hostname = normalizeDomainName(hostname)
+	domainName = normalizeDomainName(domainName)
+	return hostname == domainName || strings.HasSuffix(hostname, "."+domainName)
+}
+
 func resourceDomainNameserversImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	d.Set("domain", d.Id())
 	return []*schema.ResourceData{d}, nil
@@ -73,10 +142,29 @@ func resourceDomainNameserversRead(d *schema.ResourceData, m interface{}) error
 		return fmt.Errorf("failed to parse nameservers of domain %q: %s", domain, err)
 	}
 
+	d.Set("unicode_domain", domainNameToUnicode(domain))
 	d.SetId(domain)
 	return nil
 }
 
+func resourceDomainNameserversCreate(d *schema.ResourceData, m interface{}) error {
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	// Remember the current nameservers so they can be restored on destroy
+	domain := d.Get("domain").(string)
+	nameservers, err := repository.GetNameservers(domain)
+	if err != nil {
+		return fmt.Errorf("failed to get nameservers of domain %q: %s", domain, err)
+	}
+	err = d.Set("previous_nameserver", nameserversToMaps(nameservers))
+	if err != nil {
+		return fmt.Errorf("failed to parse nameservers of domain %q: %s", domain, err)
+	}
+
+	return resourceDomainNameserversUpdate(d, m)
+}
+
 func resourceDomainNameserversUpdate(d *schema.ResourceData, m interface{}) error {
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
@@ -89,6 +177,18 @@ func resourceDomainNameserversUpdate(d *schema.ResourceData, m interface{}) erro
 	}
 
 	d.SetId(domain)
+
+	// The registry changes the nameservers through a domain action
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if d.IsNewResource() {
+		timeout = d.Timeout(schema.TimeoutCreate)
+	}
+	order := domainOrder{DomainName: domain, Nameservers: nameservers}
+	err = waitForDomainAction(client, domain, timeout, d.Get("action_retries").(int), order)
+	if err != nil {
+		return fmt.Errorf("Error waiting for nameservers of domain %q to be updated: %s", domain, err)
+	}
+
 	return nil
 }
 
@@ -96,10 +196,22 @@ func resourceDomainNameserversDelete(d *schema.ResourceData, m interface{}) erro
 	client := m.(repository.Client)
 	repository := domain.Repository{Client: client}
 
-	nameservers := make([]domain.Nameserver, 3)
-	nameservers[0] = domain.Nameserver{Hostname: "ns0.transip.net"}
-	nameservers[1] = domain.Nameserver{Hostname: "ns1.transip.nl"}
-	nameservers[2] = domain.Nameserver{Hostname: "ns2.transip.eu"}
+	nameservers := interfacesToNameservers(d.Get("previous_nameserver").([]interface{}))
+	if v, ok := d.GetOk("on_destroy_nameservers"); ok {
+		nameservers = nil
+		for _, hostname := range v.([]interface{}) {
+			nameservers = append(nameservers, domain.Nameserver{Hostname: hostname.(string)})
+		}
+	}
+
+	// Fall back to the TransIP nameservers when the previous nameservers are
+	// not known, for example for imported resources.
+	if len(nameservers) == 0 {
+		nameservers = make([]domain.Nameserver, 3)
+		nameservers[0] = domain.Nameserver{Hostname: "ns0.transip.net"}
+		nameservers[1] = domain.Nameserver{Hostname: "ns1.transip.nl"}
+		nameservers[2] = domain.Nameserver{Hostname: "ns2.transip.eu"}
+	}
 
 	domain := d.Get("domain").(string)
 	err := repository.UpdateNameservers(domain, nameservers)
@@ -119,7 +231,7 @@ func nameserversToMaps(nameservers []domain.Nameserver) []map[string]interface{}
 		if v.IPv4 != nil {
 			maps[i]["ipv4"] = v.IPv4.String()
 		}
-		if v.IPv4 != nil {
+		if v.IPv6 != nil {
 			maps[i]["ipv6"] = v.IPv6.String()
 		}
 	}
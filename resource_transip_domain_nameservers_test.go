@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+)
+
+func TestNameserversToMaps(t *testing.T) {
+	nameservers := []domain.Nameserver{
+		{Hostname: "ns0.transip.net"},
+		{Hostname: "ns1.example.com", IPv4: net.ParseIP("192.0.2.1")},
+		{Hostname: "ns2.example.com", IPv6: net.ParseIP("2001:db8::1")},
+	}
+
+	expected := []map[string]interface{}{
+		{"hostname": "ns0.transip.net"},
+		{"hostname": "ns1.example.com", "ipv4": "192.0.2.1"},
+		{"hostname": "ns2.example.com", "ipv6": "2001:db8::1"},
+	}
+	if actual := nameserversToMaps(nameservers); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestIsInBailiwick(t *testing.T) {
+	for _, c := range []struct {
+		hostname, domainName string
+		expected             bool
+	}{
+		{"ns1.example.com", "example.com", true},
+		{"NS1.Example.com.", "example.com", true},
+		{"example.com", "example.com", true},
+		{"ns1.notexample.com", "example.com", false},
+		{"ns1.example.org", "example.com", false},
+	} {
+		if actual := isInBailiwick(c.hostname, c.domainName); actual != c.expected {
+			t.Errorf("expected %v for %s in %s", c.expected, c.hostname, c.domainName)
+		}
+	}
+}
+
+func TestResourceDomainNameserversRestore(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "GET" && r.endpoint == "/domains/example.com/nameservers":
+			return `{"nameservers":[{"hostname":"ns1.example.org","ipv4":"","ipv6":""}]}`, nil
+		case r.method == "PUT" && r.endpoint == "/domains/example.com/nameservers":
+			return "", nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com":
+			return `{"domain":{"name":"example.com"}}`, nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com/actions":
+			return `{"action":{"name":"","message":"","hasFailed":false}}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceDomainNameservers().Schema, map[string]interface{}{
+		"domain": "example.com",
+		"nameserver": []interface{}{
+			map[string]interface{}{"hostname": "ns1.example.net"},
+		},
+	})
+
+	if err := resourceDomainNameserversCreate(d, client); err != nil {
+		t.Fatal(err)
+	}
+	previous := d.Get("previous_nameserver").([]interface{})
+	if len(previous) != 1 || previous[0].(map[string]interface{})["hostname"] != "ns1.example.org" {
+		t.Errorf("expected the nameservers before create to be captured, got %v", previous)
+	}
+
+	if err := resourceDomainNameserversDelete(d, client); err != nil {
+		t.Fatal(err)
+	}
+	puts := client.requestsWithMethod("PUT")
+	if len(puts) != 2 {
+		t.Fatalf("expected the nameservers to be updated on create and destroy, got %v", puts)
+	}
+	if expected := `{"nameservers":[{"hostname":"ns1.example.net"}]}`; puts[0].body != expected {
+		t.Errorf("expected %s on create, got %s", expected, puts[0].body)
+	}
+	if expected := `{"nameservers":[{"hostname":"ns1.example.org"}]}`; puts[1].body != expected {
+		t.Errorf("expected the previous nameservers %s to be restored, got %s", expected, puts[1].body)
+	}
+}
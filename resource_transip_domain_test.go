@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestResourceDomainReadFailedAction(t *testing.T) {
+	action := `{"action":{"name":"changeNameservers","message":"nameserver ns1.example.net is not reachable","hasFailed":true}}`
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch r.endpoint {
+		case "/domains/example.com":
+			return `{"domain":{"name":"example.com"}}`, nil
+		case "/domains/example.com/actions":
+			return action, nil
+		}
+		return "", fmt.Errorf("unexpected request %s", r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceDomain().Schema, map[string]interface{}{"name": "example.com"})
+	d.SetId("example.com")
+
+	if err := resourceDomainRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Get("failed_action").(string); got != "changeNameservers" {
+		t.Errorf("expected failed_action changeNameservers, got %q", got)
+	}
+	if got := d.Get("failed_action_message").(string); got != "nameserver ns1.example.net is not reachable" {
+		t.Errorf("unexpected failed_action_message %q", got)
+	}
+
+	// a running action that has not failed is not reported
+	action = `{"action":{"name":"changeNameservers","message":"","hasFailed":false}}`
+	if err := resourceDomainRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if got := d.Get("failed_action").(string); got != "" {
+		t.Errorf("expected no failed_action, got %q", got)
+	}
+}
+
+func TestResourceDomainCreateOrder(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "POST" && r.endpoint == "/domains":
+			return "", nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com":
+			return `{"domain":{"name":"example.com"}}`, nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com/actions":
+			return `{"action":{"name":"","message":"","hasFailed":false}}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceDomain().Schema, map[string]interface{}{
+		"name": "example.com",
+		"nameserver": []interface{}{
+			map[string]interface{}{"hostname": "ns1.example.net"},
+		},
+		"dns_entry": []interface{}{
+			map[string]interface{}{"name": "www", "expire": 300, "type": "A", "content": "192.0.2.1"},
+		},
+	})
+
+	if err := resourceDomainCreate(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := client.requestsWithMethod("POST")
+	if len(posts) != 1 {
+		t.Fatalf("expected a single domain order, got %v", posts)
+	}
+	expected := `{"domainName":"example.com","dnsEntries":[{"name":"www","expire":300,"type":"A","content":"192.0.2.1"}],"nameservers":[{"hostname":"ns1.example.net"}]}`
+	if posts[0].body != expected {
+		t.Errorf("expected domain order %s, got %s", expected, posts[0].body)
+	}
+}
+
+func TestResourceDomainRegistrationChanges(t *testing.T) {
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name": "example.com",
+		"nameserver": []interface{}{
+			map[string]interface{}{"hostname": "ns2.example.net"},
+		},
+	})
+
+	registered := &terraform.InstanceState{
+		ID: "example.com",
+		Attributes: map[string]string{
+			"id":                    "example.com",
+			"name":                  "example.com",
+			"nameserver.#":          "1",
+			"nameserver.0.hostname": "ns1.example.net",
+		},
+	}
+	if _, err := resourceDomain().Diff(registered, config, nil); err == nil {
+		t.Error("expected changing the nameservers of a registered domain to fail the plan")
+	}
+
+	imported := &terraform.InstanceState{
+		ID:         "example.com",
+		Attributes: map[string]string{"id": "example.com", "name": "example.com"},
+	}
+	if _, err := resourceDomain().Diff(imported, config, nil); err != nil {
+		t.Errorf("expected the nameservers of an imported domain to be adopted, got %s", err)
+	}
+}
+
+func TestResourceDomainUpdate(t *testing.T) {
+	domainResponse := `{"domain":{"name":"example.com","tags":[]}}`
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "GET" && r.endpoint == "/domains/example.com":
+			return domainResponse, nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com/actions":
+			return `{"action":{"name":"","message":"","hasFailed":false}}`, nil
+		case r.method == "PUT" && r.endpoint == "/domains/example.com":
+			return "", nil
+		case r.method == "DELETE" && r.endpoint == "/domains/example.com":
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+
+	state := &terraform.InstanceState{
+		ID: "example.com",
+		Attributes: map[string]string{
+			"id":                 "example.com",
+			"name":               "example.com",
+			"is_transfer_locked": "false",
+			"is_whitelabel":      "false",
+			"tags.#":             "0",
+			"auto_renew":         "true",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":               "example.com",
+		"is_transfer_locked": true,
+		"is_whitelabel":      true,
+		"tags":               []interface{}{"web"},
+		"auto_renew":         false,
+	})
+
+	diff, err := resourceDomain().Diff(state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := schema.InternalMap(resourceDomain().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resourceDomainUpdate(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	puts := client.requestsWithMethod("PUT")
+	if len(puts) != 1 {
+		t.Fatalf("expected a single domain update, got %v", puts)
+	}
+	var body struct {
+		Domain struct {
+			IsTransferLocked bool
+			IsWhitelabel     bool
+			Tags             []string
+		}
+	}
+	if err := json.Unmarshal([]byte(puts[0].body), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.Domain.IsTransferLocked || !body.Domain.IsWhitelabel || !reflect.DeepEqual(body.Domain.Tags, []string{"web"}) {
+		t.Errorf("unexpected domain update %s", puts[0].body)
+	}
+
+	deletes := client.requestsWithMethod("DELETE")
+	if expected := `{"endTime":"end"}`; len(deletes) != 1 || deletes[0].body != expected {
+		t.Errorf("expected disabling auto_renew to cancel with %s, got %v", expected, deletes)
+	}
+
+	// disabling auto renew is refused with deletion protection
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":                "example.com",
+		"auto_renew":          false,
+		"deletion_protection": true,
+	})
+	if _, err := resourceDomain().Diff(state, config, nil); err == nil {
+		t.Error("expected disabling auto_renew with deletion_protection to fail the plan")
+	}
+
+	// a cancelled domain can not be renewed again
+	state.Attributes["auto_renew"] = "false"
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{"name": "example.com", "auto_renew": true})
+	if _, err := resourceDomain().Diff(state, config, nil); err == nil {
+		t.Error("expected enabling auto_renew of a cancelled domain to fail the plan")
+	}
+}
+
+func TestResourceDomainReadDrift(t *testing.T) {
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch r.endpoint {
+		case "/domains/example.com":
+			return `{"domain":{"name":"example.com","isTransferLocked":true,"tags":["mail"],"cancellationStatus":"cancelled","cancellationDate":"2026-12-31 00:00:00"}}`, nil
+		case "/domains/example.com/actions":
+			return `{"action":{"name":"","message":"","hasFailed":false}}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s", r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceDomain().Schema, map[string]interface{}{
+		"name":               "example.com",
+		"is_transfer_locked": false,
+		"tags":               []interface{}{"web"},
+		"auto_renew":         true,
+	})
+	d.SetId("example.com")
+
+	if err := resourceDomainRead(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if !d.Get("is_transfer_locked").(bool) {
+		t.Error("expected is_transfer_locked to be refreshed")
+	}
+	if tags := expandStringSet(d.Get("tags").(*schema.Set)); !reflect.DeepEqual(tags, []string{"mail"}) {
+		t.Errorf("expected tags to be refreshed, got %v", tags)
+	}
+	if d.Get("auto_renew").(bool) {
+		t.Error("expected a domain cancelled elsewhere to have auto_renew disabled")
+	}
+	if got := d.Get("cancellation_date").(string); got != "2026-12-31 00:00:00" {
+		t.Errorf("unexpected cancellation_date %q", got)
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+)
+
+func resourceDomainTransfer() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceDomainTransferCreate,
+		Read:   resourceDomainTransferRead,
+		Update: resourceDomainTransferUpdate,
+		Delete: resourceDomainTransferDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceDomainTransferImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		CustomizeDiff: resourceDomainTransferCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:             schema.TypeString,
+				Description:      "The name, including the tld of the domain to transfer",
+				Required:         true,
+				ForceNew:         true,
+				ValidateFunc:     validateDomainName,
+				StateFunc:        stateDomainName,
+				DiffSuppressFunc: suppressDomainNameDiff,
+			},
+			"unicode_name": unicodeDomainSchema(),
+			"auth_code": {
+				Type:        schema.TypeString,
+				Description: "The authcode (or EPP code) of the domain, as generated by the registry",
+				Required:    true,
+				Sensitive:   true,
+			},
+			"contact": {
+				Type:        schema.TypeList,
+				Description: "WHOIS contacts to transfer the domain with, instead of the account defaults",
+				Optional:    true,
+				Elem:        whoisContactSchema(),
+			},
+			"nameserver": {
+				Type:        schema.TypeList,
+				Description: "Nameservers to transfer the domain with, instead of the TransIP nameservers",
+				Optional:    true,
+				Elem:        nameserverSchema(),
+			},
+			"dns_entry": {
+				Type:        schema.TypeList,
+				Description: "DNS entries to transfer the domain with",
+				Optional:    true,
+				Elem:        dnsEntrySchema(),
+			},
+			"wait_for_completion": {
+				Type:        schema.TypeBool,
+				Description: "Wait until the transfer is completed, instead of only starting it",
+				Optional:    true,
+				Default:     false,
+			},
+			"action_retries": domainActionRetriesSchema(),
+			"retry_trigger": {
+				Type:        schema.TypeString,
+				Description: "Any value, changing it retries a failed transfer",
+				Optional:    true,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "The status of the transfer, either 'pending', 'failed' or 'completed'",
+				Computed:    true,
+			},
+			"action": {
+				Type:        schema.TypeString,
+				Description: "The name of the domain action that is running for the transfer",
+				Computed:    true,
+			},
+			"message": {
+				Type:        schema.TypeString,
+				Description: "The message of the domain action, explaining why the transfer failed",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+// Arguments of the transfer that are only sent to the registry when a failed
+// transfer is retried
+var domainTransferRetryArguments = []string{"auth_code", "contact", "nameserver", "dns_entry"}
+
+// Changes to the transfer can only be used to retry it once it has failed, so
+// the plan fails instead of keeping changes that are never sent.
+func resourceDomainTransferCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	status := d.Get("status").(string)
+	if d.Id() == "" || status == "failed" {
+		return nil
+	}
+	if k := changedDomainTransferArgument(d); k != "" {
+		return fmt.Errorf("%s of the transfer of domain %q can only be changed to retry a failed transfer, the transfer is %s", k, d.Id(), status)
+	}
+	return nil
+}
+
+// Implemented by both schema.ResourceData and schema.ResourceDiff
+type resourceChanges interface {
+	HasChange(key string) bool
+	GetChange(key string) (interface{}, interface{})
+}
+
+// Returns the first retry argument that changed, or an empty string. Imported
+// transfers have none of the arguments in state, the configured ones are
+// adopted without counting as a change.
+func changedDomainTransferArgument(d resourceChanges) string {
+	for _, k := range domainTransferRetryArguments {
+		if !d.HasChange(k) {
+			continue
+		}
+		switch old, _ := d.GetChange(k); old := old.(type) {
+		case string:
+			if old == "" {
+				continue
+			}
+		case []interface{}:
+			if len(old) == 0 {
+				continue
+			}
+		}
+		return k
+	}
+	return ""
+}
+
+func resourceDomainTransferImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	d.Set("name", d.Id())
+	d.Set("wait_for_completion", false)
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceDomainTransferCreate(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("name").(string)
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	transfer := domainOrder{
+		DomainName:  name,
+		AuthCode:    d.Get("auth_code").(string),
+		Contacts:    interfacesToWhoisContacts(d.Get("contact").([]interface{})),
+		DNSEntries:  interfacesToDNSEntries(d.Get("dns_entry").([]interface{})),
+		Nameservers: interfacesToNameservers(d.Get("nameserver").([]interface{})),
+	}
+	err := postDomainOrder(client, transfer)
+	if err != nil {
+		return fmt.Errorf("failed to transfer domain %q: %s", name, err)
+	}
+
+	err = resource.Retry(30*time.Second, func() *resource.RetryError {
+		_, err := repository.GetByDomainName(name)
+		if err != nil {
+			return resource.RetryableError(err)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("Error waiting for domain transfer to be started: %s", err)
+	}
+
+	d.SetId(name)
+
+	if d.Get("wait_for_completion").(bool) {
+		err = waitForDomainAction(client, name, d.Timeout(schema.TimeoutCreate), d.Get("action_retries").(int), transfer)
+		if err != nil {
+			return fmt.Errorf("Error waiting for domain transfer to complete: %s", err)
+		}
+	}
+
+	return resourceDomainTransferRead(d, m)
+}
+
+func resourceDomainTransferRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Id()
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	_, err := repository.GetByDomainName(name)
+	if err != nil {
+		return fmt.Errorf("failed to lookup domain %q: %s", name, err)
+	}
+
+	action, err := getDomainAction(repository, name)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case action == nil:
+		d.Set("status", "completed")
+		d.Set("action", "")
+		d.Set("message", "")
+	case action.HasFailed:
+		d.Set("status", "failed")
+		d.Set("action", action.Name)
+		d.Set("message", action.Message)
+	default:
+		d.Set("status", "pending")
+		d.Set("action", action.Name)
+		d.Set("message", action.Message)
+	}
+
+	d.Set("name", name)
+	d.Set("unicode_name", domainNameToUnicode(name))
+	return nil
+}
+
+// A failed transfer is retried with the current authcode, contacts,
+// nameservers and DNS entries whenever any of them or the trigger changes.
+func resourceDomainTransferUpdate(d *schema.ResourceData, m interface{}) error {
+	name := d.Id()
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	if !d.HasChanges("auth_code", "contact", "nameserver", "dns_entry", "retry_trigger") {
+		return resourceDomainTransferRead(d, m)
+	}
+
+	action, err := getDomainAction(repository, name)
+	if err != nil {
+		return err
+	}
+	if action == nil || !action.HasFailed {
+		// the transfer was retried or completed after the plan
+		if k := changedDomainTransferArgument(d); k != "" {
+			return fmt.Errorf("transfer of domain %q has not failed, it can not be retried with the changed %s", name, k)
+		}
+		log.Printf("[WARN] terraform-provider-transip: transfer of domain %s has not failed, it is not retried\n", name)
+		return resourceDomainTransferRead(d, m)
+	}
+
+	transfer := domainOrder{
+		DomainName:  name,
+		AuthCode:    d.Get("auth_code").(string),
+		Contacts:    interfacesToWhoisContacts(d.Get("contact").([]interface{})),
+		DNSEntries:  interfacesToDNSEntries(d.Get("dns_entry").([]interface{})),
+		Nameservers: interfacesToNameservers(d.Get("nameserver").([]interface{})),
+	}
+
+	log.Printf("[DEBUG] terraform-provider-transip: retrying domain action %q of domain %s\n", action.Name, name)
+	err = repository.RetryDomainAction(name, transfer.AuthCode, transfer.DNSEntries, transfer.Nameservers, transfer.Contacts)
+	if err != nil {
+		return fmt.Errorf("failed to retry transfer of domain %q: %s", name, err)
+	}
+
+	if d.Get("wait_for_completion").(bool) {
+		err = waitForDomainAction(client, name, d.Timeout(schema.TimeoutUpdate), d.Get("action_retries").(int), transfer)
+		if err != nil {
+			return fmt.Errorf("Error waiting for domain transfer to complete: %s", err)
+		}
+	}
+
+	return resourceDomainTransferRead(d, m)
+}
+
+// Cancels the transfer when it is still pending or has failed. A completed
+// transfer is only removed from the state, the domain itself is kept.
+func resourceDomainTransferDelete(d *schema.ResourceData, m interface{}) error {
+	name := d.Id()
+
+	client := m.(repository.Client)
+	repository := domain.Repository{Client: client}
+
+	action, err := getDomainAction(repository, name)
+	if err != nil {
+		return err
+	}
+	if action != nil {
+		log.Printf("[DEBUG] terraform-provider-transip: cancelling domain action %q of domain %s\n", action.Name, name)
+		err = repository.CancelDomainAction(name)
+		if err != nil {
+			return fmt.Errorf("failed to cancel transfer of domain %q: %s", name, err)
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
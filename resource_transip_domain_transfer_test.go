@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// Answers the domain and action requests of a transfer, with the given action
+// of the domain
+func newDomainTransferClient(action *string) *fakeClient {
+	return &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "POST" && r.endpoint == "/domains":
+			return "", nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com":
+			return `{"domain":{"name":"example.com"}}`, nil
+		case r.method == "GET" && r.endpoint == "/domains/example.com/actions":
+			return *action, nil
+		case r.method == "PATCH" && r.endpoint == "/domains/example.com/actions":
+			*action = `{"action":{"name":"transfer","message":"","hasFailed":false}}`
+			return "", nil
+		case r.method == "DELETE" && r.endpoint == "/domains/example.com/actions":
+			*action = `{"action":{"name":"","message":"","hasFailed":false}}`
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+}
+
+func TestResourceDomainTransferCreate(t *testing.T) {
+	action := `{"action":{"name":"transfer","message":"","hasFailed":false}}`
+	client := newDomainTransferClient(&action)
+
+	d := schema.TestResourceDataRaw(t, resourceDomainTransfer().Schema, map[string]interface{}{
+		"name":      "example.com",
+		"auth_code": "secret",
+		"nameserver": []interface{}{
+			map[string]interface{}{"hostname": "ns1.example.net"},
+		},
+	})
+
+	if err := resourceDomainTransferCreate(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	posts := client.requestsWithMethod("POST")
+	expected := `{"domainName":"example.com","authCode":"secret","nameservers":[{"hostname":"ns1.example.net"}]}`
+	if len(posts) != 1 || posts[0].body != expected {
+		t.Errorf("expected transfer order %s, got %v", expected, posts)
+	}
+	if status := d.Get("status").(string); status != "pending" {
+		t.Errorf("expected status pending, got %q", status)
+	}
+}
+
+func TestResourceDomainTransferRetry(t *testing.T) {
+	action := `{"action":{"name":"transfer","message":"invalid authcode","hasFailed":true}}`
+	client := newDomainTransferClient(&action)
+
+	state := &terraform.InstanceState{
+		ID: "example.com",
+		Attributes: map[string]string{
+			"id":        "example.com",
+			"name":      "example.com",
+			"auth_code": "wrong",
+			"status":    "failed",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":      "example.com",
+		"auth_code": "secret",
+	})
+
+	diff, err := resourceDomainTransfer().Diff(state, config, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := schema.InternalMap(resourceDomainTransfer().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := resourceDomainTransferUpdate(d, client); err != nil {
+		t.Fatal(err)
+	}
+
+	patches := client.requestsWithMethod("PATCH")
+	expected := `{"authCode":"secret","contacts":[]}`
+	if len(patches) != 1 || patches[0].body != expected {
+		t.Errorf("expected the transfer to be retried with %s, got %v", expected, patches)
+	}
+	if status := d.Get("status").(string); status != "pending" {
+		t.Errorf("expected the retried transfer to be pending, got %q", status)
+	}
+
+	// changes to a transfer that has not failed are never sent
+	state.Attributes["status"] = "pending"
+	if _, err := resourceDomainTransfer().Diff(state, config, client); err == nil {
+		t.Error("expected changing a pending transfer to fail the plan")
+	}
+}
+
+func TestResourceDomainTransferDelete(t *testing.T) {
+	action := `{"action":{"name":"transfer","message":"","hasFailed":false}}`
+	client := newDomainTransferClient(&action)
+
+	d := schema.TestResourceDataRaw(t, resourceDomainTransfer().Schema, map[string]interface{}{
+		"name":      "example.com",
+		"auth_code": "secret",
+	})
+	d.SetId("example.com")
+
+	if err := resourceDomainTransferDelete(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if deletes := client.requestsWithMethod("DELETE"); len(deletes) != 1 {
+		t.Errorf("expected the pending transfer to be cancelled, got %v", deletes)
+	}
+
+	// a completed transfer keeps the domain
+	client.requests = nil
+	d.SetId("example.com")
+	if err := resourceDomainTransferDelete(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if deletes := client.requestsWithMethod("DELETE"); len(deletes) != 0 {
+		t.Errorf("expected a completed transfer not to be cancelled, got %v", deletes)
+	}
+}
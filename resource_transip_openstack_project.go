@@ -33,6 +33,7 @@ func resourceOpenstackProject() *schema.Resource {
 				Optional:    true,
 				ForceNew:    false,
 			},
+			"deletion_protection": deletionProtectionSchema(),
 			"locked": {
 				Type:        schema.TypeBool,
 				Description: "Set to true when an ongoing process blocks the project from being modified",
@@ -122,7 +123,14 @@ func resourceOpenstackProjectUpdate(d *schema.ResourceData, m interface{}) error
 func resourceOpenstackProjectDelete(d *schema.ResourceData, m interface{}) error {
 	client := m.(repository.Client)
 	repository := openstack.ProjectRepository{Client: client}
-	err := repository.Cancel(d.Id())
+
+	err := checkDeletionProtection(d, "openstack project", d.Get("name").(string))
+	if err != nil {
+		return err
+	}
+
+	// The API only cancels openstack projects immediately
+	err = repository.Cancel(d.Id())
 	if err != nil {
 		return fmt.Errorf("failed to delete openstack project %q: %s", d.Get("name"), err)
 	}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"github.com/transip/gotransip/v6"
 	"github.com/transip/gotransip/v6/repository"
 	"github.com/transip/gotransip/v6/vps"
 )
@@ -40,6 +39,8 @@ func resourcePrivateNetwork() *schema.Resource {
 				Description: "When locked, another process is already working with this private network.",
 				Computed:    true,
 			},
+			"cancellation_time":   cancellationTimeSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 			"vps_names": {
 				Type:        schema.TypeList,
 				Description: "The VPSes in this private network.",
@@ -110,7 +111,12 @@ func resourcePrivateNetworkDelete(d *schema.ResourceData, m interface{}) error {
 	client := m.(repository.Client)
 	repository := vps.PrivateNetworkRepository{Client: client}
 
-	err := repository.Cancel(d.Id(), gotransip.CancellationTimeImmediately)
+	err := checkDeletionProtection(d, "private network", d.Id())
+	if err != nil {
+		return err
+	}
+
+	err = repository.Cancel(d.Id(), cancellationTime(d, "private network", d.Id()))
 	if err != nil {
 		return fmt.Errorf("failed to cancel private network %s with id %q: %s", description, d.Id(), err)
 	}
@@ -103,5 +103,4 @@ func resourcePrivateNetworkAttachmentDelete(d *schema.ResourceData, m interface{
 		}
 		return nil
 	})
-	return nil
 }
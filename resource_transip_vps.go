@@ -7,21 +7,28 @@ import (
 	"github.com/google/uuid"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
-	"github.com/transip/gotransip/v6"
 	"github.com/transip/gotransip/v6/product"
 	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/rest"
 	"github.com/transip/gotransip/v6/vps"
+	"log"
+	"strings"
+	"time"
 )
 
 func resourceVps() *schema.Resource {
 	return &schema.Resource{
 		Create: resourceVpsCreate,
 		Read:   resourceVpsRead,
-		// Update: resourceVpsUpdate,
+		Update: resourceVpsUpdate,
 		Delete: resourceVpsDelete,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+		CustomizeDiff: resourceVpsCustomizeDiff,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -32,7 +39,6 @@ func resourceVps() *schema.Resource {
 				Type:        schema.TypeString,
 				Description: "The name that can be set by customer.",
 				Optional:    true,
-				ForceNew:    true,
 				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
 					if len(val.(string)) > 32 {
 						errs = append(errs, fmt.Errorf("%q must be less than 33 characters", key))
@@ -42,9 +48,8 @@ func resourceVps() *schema.Resource {
 			},
 			"product_name": {
 				Type:        schema.TypeString,
-				Description: "The product name.",
+				Description: "The product name. Upgrades to a product listed by the transip_vps_upgrades data source are done in place, other changes replace the VPS.",
 				Required:    true,
-				ForceNew:    true,
 			},
 			"operating_system": {
 				Type:        schema.TypeString,
@@ -100,13 +105,25 @@ func resourceVps() *schema.Resource {
 				ForceNew:    true,
 			},
 			"tags": {
-				Type:        schema.TypeList,
+				Type:        schema.TypeSet,
 				Description: "The custom tags added to this VPS.",
-				Computed:    true,
+				Optional:    true,
 				Elem: &schema.Schema{
 					Type: schema.TypeString,
 				},
 			},
+			"addons": {
+				Type:             schema.TypeList,
+				Description:      "The addons of the VPS, like extra ip addresses, disk, memory or cpus. An addon can be listed more than once, the order does not matter. Removed addons are cancelled, storage addons can not be cancelled. When not set, the addons are left alone.",
+				Optional:         true,
+				Computed:         true,
+				DiffSuppressFunc: suppressAddonsOrder,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"cancellation_time":   cancellationTimeSchema(),
+			"deletion_protection": deletionProtectionSchema(),
 			"install_text": {
 				Type:        schema.TypeString,
 				Default:     "",
@@ -148,7 +165,8 @@ func resourceVpsCreate(d *schema.ResourceData, m interface{}) error {
 	operatingSystem := d.Get("operating_system").(string)
 	availabilityZone := d.Get("availability_zone").(string)
 	description := d.Get("description").(string)
-	addons := []string{}
+	tags := expandStringSet(d.Get("tags").(*schema.Set))
+	addons := expandAddons(d.Get("addons").([]interface{}))
 	installText := d.Get("install_text").(string)
 	installFlavour := vps.InstallFlavour(d.Get("install_flavour").(string))
 
@@ -214,7 +232,8 @@ func resourceVpsCreate(d *schema.ResourceData, m interface{}) error {
 			}
 			// replace temporary description with actual one
 			v.Description = description
-			err := repository.Update(v)
+			v.Tags = tags
+			err := updateVps(client, v)
 			if err != nil {
 				return resource.RetryableError(fmt.Errorf("Failed to update description for VPS %s.", d.Id()))
 			}
@@ -272,6 +291,12 @@ func resourceVpsRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("ipv4_addresses", ipv4Addresses)
 	d.Set("ipv6_addresses", ipv6Addresses)
 
+	addons, err := repository.GetAddons(name)
+	if err != nil {
+		return fmt.Errorf("failed to get addons of vps %q: %s", name, err)
+	}
+	d.Set("addons", activeAddonNames(addons))
+
 	// Transip API requires OS Name for creating VPS but return OS Description on a VPS query.
 	// So it needs to be translated to avoid Terraform detecting changes.
 	operatingSystems, err := repository.GetOperatingSystems("x")
@@ -287,13 +312,239 @@ func resourceVpsRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// Upgrades are done in place, the VPS is only replaced for downgrades or
+// other products that are not available as upgrade.
+func resourceVpsCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	err := resourceVpsCustomizeDiffAddons(d, m)
+	if err != nil {
+		return err
+	}
+
+	if !d.HasChange("product_name") {
+		return nil
+	}
+	if !d.NewValueKnown("product_name") {
+		return d.ForceNew("product_name")
+	}
+
+	client, ok := m.(repository.Client)
+	if !ok {
+		return fmt.Errorf("failed to check the upgrades of VPS %q, the provider is not configured", d.Id())
+	}
+	repository := vps.Repository{Client: client}
+
+	productName := d.Get("product_name").(string)
+	upgradable, err := isVpsUpgrade(repository, d.Id(), productName)
+	if err != nil {
+		return err
+	}
+	if !upgradable {
+		log.Printf("[DEBUG] terraform-provider-transip: product %s is not an upgrade of VPS %s, replacing it\n", productName, d.Id())
+		return d.ForceNew("product_name")
+	}
+
+	for _, key := range []string{"cpus", "memory_size", "disk_size"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Added addons must be available for the VPS
+func resourceVpsCustomizeDiffAddons(d *schema.ResourceDiff, m interface{}) error {
+	if !d.HasChange("addons") || !d.NewValueKnown("addons") {
+		return nil
+	}
+
+	client, ok := m.(repository.Client)
+	if !ok {
+		return fmt.Errorf("failed to check the addons of VPS %q, the provider is not configured", d.Id())
+	}
+	repository := vps.Repository{Client: client}
+
+	old, new := d.GetChange("addons")
+	added := addonsDifference(expandAddons(new.([]interface{})), expandAddons(old.([]interface{})))
+	if len(added) == 0 {
+		return nil
+	}
+
+	addons, err := repository.GetAddons(d.Id())
+	if err != nil {
+		return fmt.Errorf("failed to get addons of VPS %q: %s", d.Id(), err)
+	}
+	available := make(map[string]bool)
+	var availableNames []string
+	for _, addon := range addons.Available {
+		available[addon.Name] = true
+		availableNames = append(availableNames, addon.Name)
+	}
+	for _, addon := range added {
+		if !available[addon] {
+			return fmt.Errorf("addon %q is not available for VPS %q, available addons are: %s", addon, d.Id(), strings.Join(availableNames, ", "))
+		}
+	}
+	return nil
+}
+
+// Order the added addons and cancel the removed ones, then wait until the
+// ordered addons are active.
+func updateVpsAddons(d *schema.ResourceData, repository vps.Repository) error {
+	name := d.Id()
+
+	o, n := d.GetChange("addons")
+	oldAddons := expandAddons(o.([]interface{}))
+	newAddons := expandAddons(n.([]interface{}))
+	added := addonsDifference(newAddons, oldAddons)
+	removed := addonsDifference(oldAddons, newAddons)
+
+	for _, addon := range removed {
+		log.Printf("[DEBUG] terraform-provider-transip: cancelling addon %s of VPS %s\n", addon, name)
+		err := repository.CancelAddon(name, addon)
+		if err != nil {
+			return fmt.Errorf("failed to cancel addon %q of VPS %q: %s", addon, name, err)
+		}
+	}
+
+	if len(added) == 0 {
+		return nil
+	}
+
+	err := repository.OrderAddons(name, added)
+	if err != nil {
+		return fmt.Errorf("failed to order addons %v of VPS %q: %s", added, name, err)
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		addons, err := repository.GetAddons(name)
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("failed to get addons of VPS %q: %s", name, err))
+		}
+		missing := addonsDifference(newAddons, activeAddonNames(addons))
+		if len(missing) > 0 {
+			return resource.RetryableError(fmt.Errorf("addons %s of VPS %s are not yet active", strings.Join(missing, ", "), name))
+		}
+		return nil
+	})
+}
+
+func isVpsUpgrade(repository vps.Repository, name string, productName string) (bool, error) {
+	upgrades, err := repository.GetUpgrades(name)
+	if err != nil {
+		return false, fmt.Errorf("failed to get upgrades of VPS %q: %s", name, err)
+	}
+	for _, upgrade := range upgrades {
+		if upgrade.Name == productName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Upgrade the VPS and wait until the new product is visible and the VPS is no
+// longer locked by the upgrade.
+func upgradeVps(d *schema.ResourceData, repository vps.Repository) error {
+	name := d.Id()
+	productName := d.Get("product_name").(string)
+
+	err := repository.Upgrade(name, productName)
+	if err != nil {
+		return fmt.Errorf("failed to upgrade VPS %q to %s: %s", name, productName, err)
+	}
+
+	return resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		v, err := repository.GetByName(name)
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("failed to lookup vps %q: %s", name, err))
+		}
+		if v.ProductName != productName {
+			return resource.RetryableError(fmt.Errorf("VPS %s is not yet upgraded to %s", name, productName))
+		}
+		if v.IsLocked {
+			return resource.RetryableError(fmt.Errorf("VPS %s is still locked by the upgrade", name))
+		}
+		return nil
+	})
+}
+
+func resourceVpsUpdate(d *schema.ResourceData, m interface{}) error {
+	name := d.Id()
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+
+	if d.HasChange("product_name") {
+		err := upgradeVps(d, repository)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("addons") {
+		err := updateVpsAddons(d, repository)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChanges("description", "tags") {
+		v, err := repository.GetByName(name)
+		if err != nil {
+			return fmt.Errorf("failed to lookup vps %q: %s", name, err)
+		}
+
+		v.Description = d.Get("description").(string)
+		v.Tags = expandStringSet(d.Get("tags").(*schema.Set))
+
+		err = updateVps(client, v)
+		if err != nil {
+			return fmt.Errorf("failed to update vps %q: %s", name, err)
+		}
+	}
+
+	return resourceVpsRead(d, m)
+}
+
+// Body of a VPS update. The gotransip Vps type omits empty tags, so removing
+// the last tag would be ignored, the request is therefore put directly.
+type vpsUpdate struct {
+	Vps struct {
+		vps.Vps
+		Tags []string `json:"tags"`
+	} `json:"vps"`
+}
+
+func newVpsUpdate(v vps.Vps) vpsUpdate {
+	var update vpsUpdate
+	update.Vps.Vps = v
+	update.Vps.Tags = v.Tags
+	if update.Vps.Tags == nil {
+		update.Vps.Tags = []string{}
+	}
+	return update
+}
+
+func updateVps(client repository.Client, v vps.Vps) error {
+	update := newVpsUpdate(v)
+	return client.Put(rest.Request{Endpoint: fmt.Sprintf("/vps/%s", v.Name), Body: &update})
+}
+
 func resourceVpsDelete(d *schema.ResourceData, m interface{}) error {
 	name := d.Get("name").(string)
 
 	client := m.(repository.Client)
 	repository := vps.Repository{Client: client}
 
-	err := repository.Cancel(name, gotransip.CancellationTimeImmediately)
+	err := checkDeletionProtection(d, "VPS", name)
+	if err != nil {
+		return err
+	}
+
+	err = repository.Cancel(name, cancellationTime(d, "VPS", name))
 	if err != nil {
 		return fmt.Errorf("failed to cancel VPS %q: %s", name, err)
 	}
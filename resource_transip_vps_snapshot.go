@@ -0,0 +1,329 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/rest"
+	"github.com/transip/gotransip/v6/vps"
+)
+
+// How long to wait for a revert to start, after which it is assumed to have
+// completed already
+const vpsRevertStartTimeout = 2 * time.Minute
+
+var errVpsRevertNotStarted = errors.New("revert has not yet started")
+
+// Errors returned while another action is running on the VPS
+var vpsBusyErrorStrings = []string{
+	"has an action running, no modification is allowed",
+	"is already locked to another action",
+}
+
+func resourceVpsSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceVpsSnapshotCreate,
+		Read:   resourceVpsSnapshotRead,
+		Update: resourceVpsSnapshotUpdate,
+		Delete: resourceVpsSnapshotDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceVpsSnapshotImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vps_name": {
+				Type:        schema.TypeString,
+				Description: "The unique name of the VPS to snapshot.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Description: "The description of the snapshot, a unique description is generated when it is not set.",
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+			},
+			"should_start_vps": {
+				Type:        schema.TypeBool,
+				Description: "Start the VPS again once the snapshot is created.",
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+			},
+			"revert_trigger": {
+				Type:        schema.TypeString,
+				Description: "Any value, changing it reverts the VPS to this snapshot. Setting it initially does not revert.",
+				Optional:    true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "The name of the snapshot.",
+				Computed:    true,
+			},
+			"date_time_create": {
+				Type:        schema.TypeString,
+				Description: "The creation date of the snapshot.",
+				Computed:    true,
+			},
+			"disk_size": {
+				Type:        schema.TypeInt,
+				Description: "The size of the snapshot in kB.",
+				Computed:    true,
+			},
+			"operating_system": {
+				Type:        schema.TypeString,
+				Description: "The operating system of the snapshot.",
+				Computed:    true,
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "The status of the snapshot, either 'active', 'creating', 'reverting', 'deleting', 'pendingDeletion', 'syncing' or 'moving'.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func vpsSnapshotID(vpsName string, snapshotName string) string {
+	return fmt.Sprintf("%s/%s", vpsName, snapshotName)
+}
+
+func parseVpsSnapshotID(id string) (string, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid VPS snapshot id %q, expected '<vps name>/<snapshot name>'", id)
+	}
+	return parts[0], parts[1], nil
+}
+
+func isVpsBusyError(err error) bool {
+	for _, errorString := range vpsBusyErrorStrings {
+		if strings.Contains(err.Error(), errorString) {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceVpsSnapshotImport(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	vpsName, _, err := parseVpsSnapshotID(d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.Set("vps_name", vpsName)
+	d.Set("should_start_vps", true)
+	return []*schema.ResourceData{d}, nil
+}
+
+// The API does not return the name of a new snapshot, so it is the snapshot
+// that was not there before it was created, with the same description. Without
+// a description a unique one is generated, so a snapshot created at the same
+// time is never taken for this one.
+func resourceVpsSnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	vpsName := d.Get("vps_name").(string)
+	description := d.Get("description").(string)
+	if description == "" {
+		description = fmt.Sprintf("terraform-%s", uuid.New().String())
+	}
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+
+	existing, err := repository.GetSnapshots(vpsName)
+	if err != nil {
+		return fmt.Errorf("failed to get snapshots of VPS %q: %s", vpsName, err)
+	}
+	known := make(map[string]bool)
+	for _, snapshot := range existing {
+		known[snapshot.Name] = true
+	}
+
+	err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		err := repository.CreateSnapshot(vpsName, description, d.Get("should_start_vps").(bool))
+		if err != nil {
+			if isVpsBusyError(err) {
+				return resource.RetryableError(fmt.Errorf("failed to create snapshot of VPS %s, VPS busy: %s; retrying", vpsName, err))
+			}
+			return resource.NonRetryableError(fmt.Errorf("failed to create snapshot of VPS %q: %s", vpsName, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		snapshots, err := repository.GetSnapshots(vpsName)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("failed to get snapshots of VPS %q: %s", vpsName, err))
+		}
+		for _, snapshot := range snapshots {
+			if known[snapshot.Name] || (description != "" && snapshot.Description != description) {
+				continue
+			}
+			d.SetId(vpsSnapshotID(vpsName, snapshot.Name))
+			if snapshot.Status != vps.SnapshotStatusActive {
+				log.Printf("[DEBUG] terraform-provider-transip: waiting for snapshot %s of VPS %s, status %s\n", snapshot.Name, vpsName, snapshot.Status)
+				return resource.RetryableError(fmt.Errorf("snapshot %s of VPS %s is not yet active", snapshot.Name, vpsName))
+			}
+			return nil
+		}
+		return resource.RetryableError(fmt.Errorf("snapshot of VPS %s is not yet created", vpsName))
+	})
+	if err != nil {
+		return fmt.Errorf("Error waiting for snapshot of VPS %q to become active: %s", vpsName, err)
+	}
+
+	return resourceVpsSnapshotRead(d, m)
+}
+
+func resourceVpsSnapshotRead(d *schema.ResourceData, m interface{}) error {
+	vpsName, snapshotName, err := parseVpsSnapshotID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+
+	snapshot, err := repository.GetSnapshotByName(vpsName, snapshotName)
+	if err != nil {
+		var restErr *rest.Error
+		if errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound {
+			log.Printf("[WARN] terraform-provider-transip: snapshot %s of VPS %s not found, removing it from the state\n", snapshotName, vpsName)
+			d.SetId("")
+			return nil
+		}
+		return fmt.Errorf("failed to lookup snapshot %q of VPS %q: %s", snapshotName, vpsName, err)
+	}
+
+	d.Set("vps_name", vpsName)
+	d.Set("name", snapshot.Name)
+	d.Set("description", snapshot.Description)
+	d.Set("date_time_create", snapshot.DateTimeCreate)
+	d.Set("disk_size", snapshot.DiskSize)
+	d.Set("operating_system", snapshot.OperatingSystem)
+	d.Set("status", snapshot.Status)
+
+	return nil
+}
+
+// Revert the VPS to the snapshot when the trigger changes, and wait until the
+// VPS is available again.
+func resourceVpsSnapshotUpdate(d *schema.ResourceData, m interface{}) error {
+	if !d.HasChange("revert_trigger") {
+		return resourceVpsSnapshotRead(d, m)
+	}
+
+	vpsName, snapshotName, err := parseVpsSnapshotID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+
+	log.Printf("[INFO] terraform-provider-transip: reverting VPS %s to snapshot %s\n", vpsName, snapshotName)
+	err = resource.Retry(d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		err := repository.RevertSnapshot(vpsName, snapshotName)
+		if err != nil {
+			if isVpsBusyError(err) {
+				return resource.RetryableError(fmt.Errorf("failed to revert VPS %s to snapshot %s, VPS busy: %s; retrying", vpsName, snapshotName, err))
+			}
+			return resource.NonRetryableError(fmt.Errorf("failed to revert VPS %q to snapshot %q: %s", vpsName, snapshotName, err))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	err = waitForVpsRevert(repository, vpsName, snapshotName, d.Get("should_start_vps").(bool), d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return fmt.Errorf("Error waiting for VPS %q to revert to snapshot %q: %s", vpsName, snapshotName, err)
+	}
+
+	return resourceVpsSnapshotRead(d, m)
+}
+
+// Wait for the revert to start, the snapshot reverting or the VPS locked, and
+// then for the VPS to be unlocked and running, or stopped when it is not
+// started again. The snapshot itself is usually active during the revert.
+func waitForVpsRevert(repository vps.Repository, vpsName string, snapshotName string, shouldStart bool, timeout time.Duration) error {
+	err := resource.Retry(vpsRevertStartTimeout, func() *resource.RetryError {
+		snapshot, err := repository.GetSnapshotByName(vpsName, snapshotName)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("failed to lookup snapshot %q of VPS %q: %s", snapshotName, vpsName, err))
+		}
+		if snapshot.Status == vps.SnapshotStatusReverting {
+			return nil
+		}
+		v, err := repository.GetByName(vpsName)
+		if err != nil {
+			return resource.NonRetryableError(fmt.Errorf("failed to lookup vps %q: %s", vpsName, err))
+		}
+		if v.IsLocked {
+			return nil
+		}
+		return resource.RetryableError(errVpsRevertNotStarted)
+	})
+	if err != nil {
+		if !errors.Is(err, errVpsRevertNotStarted) {
+			return err
+		}
+		log.Printf("[DEBUG] terraform-provider-transip: revert of VPS %s to snapshot %s not seen, assuming it has completed\n", vpsName, snapshotName)
+	}
+
+	status := vps.VpsStatusRunning
+	if !shouldStart {
+		status = vps.VpsStatusStopped
+	}
+	return resource.Retry(timeout, func() *resource.RetryError {
+		v, err := repository.GetByName(vpsName)
+		if err != nil {
+			return resource.RetryableError(fmt.Errorf("failed to lookup vps %q: %s", vpsName, err))
+		}
+		if v.IsLocked || v.Status != status {
+			log.Printf("[DEBUG] terraform-provider-transip: waiting for VPS %s to revert to snapshot %s, status %s, locked %v\n", vpsName, snapshotName, v.Status, v.IsLocked)
+			return resource.RetryableError(fmt.Errorf("VPS %s is still reverting to snapshot %s", vpsName, snapshotName))
+		}
+		return nil
+	})
+}
+
+func resourceVpsSnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	vpsName, snapshotName, err := parseVpsSnapshotID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	client := m.(repository.Client)
+	repository := vps.Repository{Client: client}
+
+	return resource.Retry(d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+		err := repository.RemoveSnapshot(vpsName, snapshotName)
+		if err != nil {
+			if isVpsBusyError(err) {
+				return resource.RetryableError(fmt.Errorf("failed to remove snapshot %s of VPS %s, VPS busy: %s; retrying", snapshotName, vpsName, err))
+			}
+			return resource.NonRetryableError(fmt.Errorf("failed to remove snapshot %q of VPS %q: %s", snapshotName, vpsName, err))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/vps"
+)
+
+func TestAccTransipResourceVpsSnapshot(t *testing.T) {
+	vpsName := os.Getenv("TF_VAR_vps_name")
+	if vpsName == "" {
+		t.Skip("TF_VAR_vps_name not provided, skipping")
+	}
+	if os.Getenv("THIS_IS_GOING_TO_COST_ME_MONEY") == "" {
+		t.Skip("THIS_IS_GOING_TO_COST_ME_MONEY not set, skipping")
+	}
+
+	timestamp := time.Now().Unix()
+	testConfig := fmt.Sprintf(`
+	resource "transip_vps_snapshot" "test" {
+		vps_name    = "%s"
+		description = "test-%d"
+	}
+	`, vpsName, timestamp)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_vps_snapshot.test", "status", "active"),
+					resource.TestCheckResourceAttr("transip_vps_snapshot.test", "description", fmt.Sprintf("test-%d", timestamp)),
+					resource.TestCheckResourceAttrSet("transip_vps_snapshot.test", "name"),
+				),
+			},
+			{
+				ResourceName:            "transip_vps_snapshot.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"revert_trigger"},
+			},
+		},
+	})
+}
+
+func TestParseVpsSnapshotID(t *testing.T) {
+	vpsName, snapshotName, err := parseVpsSnapshotID(vpsSnapshotID("example-vps", "1572607577"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vpsName != "example-vps" || snapshotName != "1572607577" {
+		t.Errorf("expected example-vps and 1572607577, got %s and %s", vpsName, snapshotName)
+	}
+
+	for _, id := range []string{"", "example-vps", "example-vps/", "/1572607577"} {
+		if _, _, err := parseVpsSnapshotID(id); err == nil {
+			t.Errorf("expected error for id %q", id)
+		}
+	}
+}
+
+func TestResourceVpsSnapshotCreateGeneratedDescription(t *testing.T) {
+	var snapshots []string
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "GET" && r.endpoint == "/vps/example-vps/snapshots":
+			return fmt.Sprintf(`{"snapshots":[%s]}`, strings.Join(snapshots, ",")), nil
+		case r.method == "POST" && r.endpoint == "/vps/example-vps/snapshots":
+			var request struct{ Description string }
+			if err := json.Unmarshal([]byte(r.body), &request); err != nil {
+				return "", err
+			}
+			// a snapshot created at the same time by someone else
+			snapshots = append(snapshots,
+				`{"name":"1000","description":"","status":"active"}`,
+				fmt.Sprintf(`{"name":"2000","description":%q,"status":"active"}`, request.Description),
+			)
+			return "", nil
+		case r.method == "GET" && r.endpoint == "/vps/example-vps/snapshots/2000":
+			return fmt.Sprintf(`{"snapshot":%s}`, snapshots[1]), nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+
+	d := schema.TestResourceDataRaw(t, resourceVpsSnapshot().Schema, map[string]interface{}{"vps_name": "example-vps"})
+	if err := resourceVpsSnapshotCreate(d, client); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "example-vps/2000" {
+		t.Errorf("expected the snapshot with the generated description, got %s", d.Id())
+	}
+	if !strings.HasPrefix(d.Get("description").(string), "terraform-") {
+		t.Errorf("expected a generated description, got %q", d.Get("description"))
+	}
+}
+
+func TestWaitForVpsRevert(t *testing.T) {
+	tests := []struct {
+		name        string
+		shouldStart bool
+		snapshot    string
+		vps         []string
+	}{
+		{"running", true, "reverting", []string{
+			`{"isLocked":true,"status":"stopped"}`,
+			`{"isLocked":false,"status":"running"}`,
+		}},
+		{"stopped", false, "active", []string{
+			`{"isLocked":true,"status":"stopped"}`,
+			`{"isLocked":false,"status":"stopped"}`,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vpsRequests := 0
+			client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+				switch r.endpoint {
+				case "/vps/example-vps/snapshots/1000":
+					return fmt.Sprintf(`{"snapshot":{"name":"1000","status":%q}}`, test.snapshot), nil
+				case "/vps/example-vps":
+					state := test.vps[len(test.vps)-1]
+					if vpsRequests < len(test.vps) {
+						state = test.vps[vpsRequests]
+					}
+					vpsRequests++
+					return fmt.Sprintf(`{"vps":%s}`, state), nil
+				}
+				return "", fmt.Errorf("unexpected request %s", r.endpoint)
+			}}
+
+			err := waitForVpsRevert(vps.Repository{Client: client}, "example-vps", "1000", test.shouldStart, time.Minute)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if vpsRequests < len(test.vps) {
+				t.Errorf("expected to wait for the VPS to be unlocked, got %d requests", vpsRequests)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/vps"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -56,6 +60,14 @@ func TestAccTransipResourceVps(t *testing.T) {
     operating_system = "ubuntu-20.04"
 	}
 	`, timestamp)
+	testConfigUpdated := fmt.Sprintf(`
+	resource "transip_vps" "test" {
+		description             = "test-%d-updated"
+    product_name     = "vps-bladevps-x2"
+    operating_system = "ubuntu-20.04"
+		tags = ["test", "terraform"]
+	}
+	`, timestamp)
 
 	resource.Test(t, resource.TestCase{
 		PreCheck:  func() { testAccPreCheck(t) },
@@ -67,6 +79,287 @@ func TestAccTransipResourceVps(t *testing.T) {
 					resource.TestCheckResourceAttr("transip_vps.test", "status", "running"),
 				),
 			},
+			{
+				Config: testConfigUpdated,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("transip_vps.test", "description", fmt.Sprintf("test-%d-updated", timestamp)),
+					resource.TestCheckResourceAttr("transip_vps.test", "tags.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestVpsUpdateBody(t *testing.T) {
+	tests := map[string][]string{
+		`{"vps":{"name":"example-vps","description":"test","tags":[]}}`:        nil,
+		`{"vps":{"name":"example-vps","description":"test","tags":["a","b"]}}`: {"a", "b"},
+	}
+
+	for expected, tags := range tests {
+		body, err := json.Marshal(newVpsUpdate(vps.Vps{Name: "example-vps", Description: "test", Tags: tags}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got, want map[string]map[string]interface{}
+		json.Unmarshal(body, &got)
+		json.Unmarshal([]byte(expected), &want)
+		for _, key := range []string{"name", "description", "tags"} {
+			if fmt.Sprint(got["vps"][key]) != fmt.Sprint(want["vps"][key]) {
+				t.Errorf("expected %s %v, got %v in %s", key, want["vps"][key], got["vps"][key], body)
+			}
+		}
+	}
+}
+
+// Answers the upgrades of example-vps, which can be upgraded to
+// vps-bladevps-x8 only
+func vpsUpgradesClient() *fakeClient {
+	return &fakeClient{handler: func(r fakeRequest) (string, error) {
+		if r.endpoint == "/vps/example-vps/upgrades" {
+			return `{"upgrades":[{"name":"vps-bladevps-x8","description":"BladeVPS X8"}]}`, nil
+		}
+		return "", fmt.Errorf("unexpected request %s", r.endpoint)
+	}}
+}
+
+func TestIsVpsUpgrade(t *testing.T) {
+	repository := vps.Repository{Client: vpsUpgradesClient()}
+
+	tests := map[string]bool{
+		"vps-bladevps-x8": true,
+		"vps-bladevps-x1": false,
+		"":                false,
+	}
+	for productName, expected := range tests {
+		got, err := isVpsUpgrade(repository, "example-vps", productName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != expected {
+			t.Errorf("isVpsUpgrade(%q): expected %v, got %v", productName, expected, got)
+		}
+	}
+
+	if _, err := isVpsUpgrade(repository, "other-vps", "vps-bladevps-x8"); err == nil {
+		t.Error("expected the lookup error to be returned")
+	}
+}
+
+func TestResourceVpsCustomizeDiffProduct(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "example-vps",
+		Attributes: map[string]string{
+			"id":                "example-vps",
+			"name":              "example-vps",
+			"description":       "example",
+			"product_name":      "vps-bladevps-x4",
+			"operating_system":  "ubuntu-20.04",
+			"availability_zone": "ams0",
+			"cpus":              "2",
+		},
+	}
+
+	tests := map[string]bool{
+		"vps-bladevps-x8": false,
+		"vps-bladevps-x1": true,
+	}
+	for productName, requiresNew := range tests {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{
+			"description":      "example",
+			"product_name":     productName,
+			"operating_system": "ubuntu-20.04",
+		})
+
+		diff, err := resourceVps().Diff(state, config, vpsUpgradesClient())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff.RequiresNew() != requiresNew {
+			t.Errorf("product %s: expected requires new %v, got %v", productName, requiresNew, diff.RequiresNew())
+		}
+		if !requiresNew && !diff.Attributes["cpus"].NewComputed {
+			t.Errorf("product %s: expected cpus to be recomputed after the upgrade", productName)
+		}
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"description":      "example",
+		"product_name":     "vps-bladevps-x8",
+		"operating_system": "ubuntu-20.04",
+	})
+	if _, err := resourceVps().Diff(state, config, nil); err == nil {
+		t.Error("expected an error without a configured provider")
+	}
+}
+
+func TestAddonsDifference(t *testing.T) {
+	tests := []struct {
+		a, b, expected []string
+	}{
+		{[]string{"ip", "ip", "cpu"}, []string{"ip"}, []string{"cpu", "ip"}},
+		{[]string{"ip"}, []string{"ip", "ip"}, nil},
+		{[]string{"cpu", "ip"}, []string{"ip", "cpu"}, nil},
+		{nil, []string{"ip"}, nil},
+	}
+	for _, test := range tests {
+		got := addonsDifference(test.a, test.b)
+		if fmt.Sprint(got) != fmt.Sprint(test.expected) {
+			t.Errorf("addonsDifference(%v, %v): expected %v, got %v", test.a, test.b, test.expected, got)
+		}
+	}
+}
+
+func TestUpdateVpsAddons(t *testing.T) {
+	const (
+		ip     = "vps-addon-1-extra-ip-address"
+		cpu    = "vps-addon-1-extra-cpu-core"
+		memory = "vps-addon-1-gb-extra-memory"
+	)
+
+	active := []string{ip, ip, cpu}
+	client := &fakeClient{}
+	client.handler = func(r fakeRequest) (string, error) {
+		switch {
+		case r.method == "GET" && r.endpoint == "/vps/example-vps/addons":
+			var products []string
+			for _, addon := range active {
+				products = append(products, fmt.Sprintf(`{"name":%q}`, addon))
+			}
+			return fmt.Sprintf(`{"addons":{"active":[%s],"available":[{"name":%q},{"name":%q},{"name":%q}]}}`,
+				strings.Join(products, ","), ip, cpu, memory), nil
+		case r.method == "DELETE":
+			cancelled := strings.TrimPrefix(r.endpoint, "/vps/example-vps/addons/")
+			active = addonsDifference(active, []string{cancelled})
+			return "", nil
+		case r.method == "POST" && r.endpoint == "/vps/example-vps/addons":
+			var order struct{ Addons []string }
+			if err := json.Unmarshal([]byte(r.body), &order); err != nil {
+				return "", err
+			}
+			active = append(active, order.Addons...)
+			return "", nil
+		}
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}
+
+	state := &terraform.InstanceState{
+		ID: "example-vps",
+		Attributes: map[string]string{
+			"id":                "example-vps",
+			"name":              "example-vps",
+			"description":       "example",
+			"product_name":      "vps-bladevps-x4",
+			"operating_system":  "ubuntu-20.04",
+			"availability_zone": "ams0",
+			"addons.#":          "3",
+			"addons.0":          cpu,
+			"addons.1":          ip,
+			"addons.2":          ip,
 		},
+	}
+	newConfig := func(addons ...interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"description":      "example",
+			"product_name":     "vps-bladevps-x4",
+			"operating_system": "ubuntu-20.04",
+			"addons":           addons,
+		})
+	}
+
+	// only the order differs
+	diff, err := resourceVps().Diff(state, newConfig(ip, cpu, ip), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range diff.Attributes {
+		if strings.HasPrefix(k, "addons.") && v.Old != v.New {
+			t.Errorf("expected no diff when only the order of the addons differs, got %s %#v", k, v)
+		}
+	}
+
+	diff, err = resourceVps().Diff(state, newConfig(memory, ip, memory), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := schema.InternalMap(resourceVps().Schema).Data(state, diff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.requests = nil
+	if err := updateVpsAddons(d, vps.Repository{Client: client}); err != nil {
+		t.Fatal(err)
+	}
+
+	var cancelled []string
+	for _, r := range client.requestsWithMethod("DELETE") {
+		cancelled = append(cancelled, strings.TrimPrefix(r.endpoint, "/vps/example-vps/addons/"))
+	}
+	if expected := []string{cpu, ip}; fmt.Sprint(cancelled) != fmt.Sprint(expected) {
+		t.Errorf("expected addons %v to be cancelled, got %v", expected, cancelled)
+	}
+
+	orders := client.requestsWithMethod("POST")
+	if expected := fmt.Sprintf(`{"addons":[%q,%q]}`, memory, memory); len(orders) != 1 || orders[0].body != expected {
+		t.Errorf("expected a single order %s, got %v", expected, orders)
+	}
+
+	if expected := []string{ip, memory, memory}; fmt.Sprint(addonsDifference(active, nil)) != fmt.Sprint(expected) {
+		t.Errorf("expected active addons %v, got %v", expected, active)
+	}
+}
+
+func TestResourceVpsDiffAddonsUnset(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "example-vps",
+		Attributes: map[string]string{
+			"id":                "example-vps",
+			"name":              "example-vps",
+			"description":       "example",
+			"product_name":      "vps-bladevps-x4",
+			"operating_system":  "ubuntu-20.04",
+			"availability_zone": "ams0",
+			"addons.#":          "2",
+			"addons.0":          "vpsAddon-1-extra-cpu-core",
+			"addons.1":          "vpsAddon-1-extra-ip-address",
+		},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"description":      "example",
+		"product_name":     "vps-bladevps-x4",
+		"operating_system": "ubuntu-20.04",
 	})
+
+	// addons ordered in the control panel are kept when addons is not set
+	diff, err := resourceVps().Diff(state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil {
+		for k, v := range diff.Attributes {
+			if strings.HasPrefix(k, "addons") {
+				t.Errorf("expected no addons diff when addons is not set, got %s %#v", k, v)
+			}
+		}
+	}
+
+	// an empty list still cancels all addons
+	config = terraform.NewResourceConfigRaw(map[string]interface{}{
+		"description":      "example",
+		"product_name":     "vps-bladevps-x4",
+		"operating_system": "ubuntu-20.04",
+		"addons":           []interface{}{},
+	})
+	client := &fakeClient{handler: func(r fakeRequest) (string, error) {
+		return "", fmt.Errorf("unexpected request %s %s", r.method, r.endpoint)
+	}}
+	diff, err = resourceVps().Diff(state, config, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := diff.Attributes["addons.#"]; !ok || v.New != "0" {
+		t.Errorf("expected all addons to be removed by an empty list, got %#v", diff.Attributes)
+	}
 }
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/transip/gotransip/v6"
+)
+
+func cancellationTimeSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "When to cancel the contract on destroy, either 'immediately' or at the 'end' of the contract period",
+		Optional:    true,
+		Default:     string(gotransip.CancellationTimeImmediately),
+		ValidateFunc: validation.StringInSlice([]string{
+			string(gotransip.CancellationTimeImmediately),
+			string(gotransip.CancellationTimeEnd),
+		}, false),
+	}
+}
+
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Description: "Refuse to destroy, and so cancel, this resource while enabled",
+		Optional:    true,
+		Default:     false,
+	}
+}
+
+// Refuse to cancel a resource that has deletion protection enabled
+func checkDeletionProtection(d *schema.ResourceData, kind string, name string) error {
+	if d.Get("deletion_protection").(bool) {
+		return fmt.Errorf("%s %q has deletion_protection enabled, disable it before destroying", kind, name)
+	}
+	return nil
+}
+
+// The configured cancellation time, logging that the contract remains active
+// until the end of its period when it is not cancelled immediately. The
+// resource is removed from the state on destroy either way, so the log is all
+// that records the pending cancellation, only domains report it in the API.
+func cancellationTime(d *schema.ResourceData, kind string, name string) gotransip.CancellationTime {
+	endTime := gotransip.CancellationTime(d.Get("cancellation_time").(string))
+	if endTime == gotransip.CancellationTimeEnd {
+		log.Printf("[INFO] terraform-provider-transip: %s %s is pending cancellation, it remains active until the end of its contract period\n", kind, name)
+	}
+	return endTime
+}
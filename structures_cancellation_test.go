@@ -0,0 +1,34 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6"
+)
+
+func TestCancellation(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"cancellation_time":   cancellationTimeSchema(),
+		"deletion_protection": deletionProtectionSchema(),
+	}
+
+	d := schema.TestResourceDataRaw(t, s, map[string]interface{}{})
+	if err := checkDeletionProtection(d, "VPS", "test"); err != nil {
+		t.Errorf("unexpected error without deletion protection: %s", err)
+	}
+	if got := cancellationTime(d, "VPS", "test"); got != gotransip.CancellationTimeImmediately {
+		t.Errorf("expected default cancellation time %q, got %q", gotransip.CancellationTimeImmediately, got)
+	}
+
+	d = schema.TestResourceDataRaw(t, s, map[string]interface{}{
+		"cancellation_time":   "end",
+		"deletion_protection": true,
+	})
+	if err := checkDeletionProtection(d, "VPS", "test"); err == nil {
+		t.Error("expected error with deletion protection enabled")
+	}
+	if got := cancellationTime(d, "VPS", "test"); got != gotransip.CancellationTimeEnd {
+		t.Errorf("expected cancellation time %q, got %q", gotransip.CancellationTimeEnd, got)
+	}
+}
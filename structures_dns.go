@@ -0,0 +1,214 @@
+package main
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+)
+
+// Name prefix of the TXT entries that record which content values of a DNS
+// record are owned by which owner, similar to the external-dns TXT registry.
+const dnsOwnerRegistryPrefix = "_terraform-owner"
+
+// A DNS record is the set of all entries with the same name and type in a zone.
+type dnsRecord struct {
+	Name    string
+	Type    string
+	Expire  int
+	Content []string
+	// When set, ownership of the content values is kept in the owner registry
+	OwnerID string
+}
+
+// Name of the registry TXT entries for a DNS entry name. Wildcards can only
+// be used as the leftmost label, so they are replaced by a regular label.
+func dnsOwnerRegistryName(entryName string) string {
+	if entryName == "@" {
+		return dnsOwnerRegistryPrefix
+	}
+	return dnsOwnerRegistryPrefix + "." + strings.Replace(entryName, "*", "_wildcard", 1)
+}
+
+func dnsOwnerRegistryOwnerPrefix(ownerID string, entryType string) string {
+	return fmt.Sprintf("heritage=terraform-provider-transip,owner=%s,type=%s,", ownerID, entryType)
+}
+
+// Content of the registry TXT entry that marks one content value of a record
+// as owned. The value itself is hashed to keep the TXT entry short.
+func dnsOwnerRegistryContent(ownerID string, entryType string, content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return fmt.Sprintf("%scontent=%x", dnsOwnerRegistryOwnerPrefix(ownerID, entryType), sum[:8])
+}
+
+// Returns the content values of the record in the zone that are managed by
+// the resource. With an owner these are the values registered to it in the
+// owner registry, otherwise the values that were previously in state.
+func dnsRecordOwnedContent(dnsEntries []domain.DNSEntry, entryName string, entryType string, ownerID string, previous []string) map[string]bool {
+	owned := make(map[string]bool)
+
+	if ownerID == "" {
+		for _, c := range previous {
+			owned[c] = true
+		}
+		return owned
+	}
+
+	registered := make(map[string]bool)
+	registryName := dnsOwnerRegistryName(entryName)
+	for _, e := range dnsEntries {
+		if e.Name == registryName && e.Type == "TXT" {
+			registered[e.Content] = true
+		}
+	}
+
+	for _, e := range dnsEntries {
+		if e.Name == entryName && e.Type == entryType && registered[dnsOwnerRegistryContent(ownerID, entryType, e.Content)] {
+			owned[e.Content] = true
+		}
+	}
+
+	return owned
+}
+
+// Determine which entries need to be removed from and added to the zone to
+// get the record into the desired state. Only entries whose content is owned
+// (or desired) are ever removed, everything else in the zone is left alone.
+// Registry entries of previousOwnerID are replaced by those of the record's
+// current owner.
+func dnsRecordChanges(dnsEntries []domain.DNSEntry, record dnsRecord, owned map[string]bool, previousOwnerID string) ([]domain.DNSEntry, []domain.DNSEntry) {
+	var remove, add []domain.DNSEntry
+
+	desired := make(map[string]bool)
+	for _, c := range record.Content {
+		desired[c] = true
+	}
+
+	present := make(map[string]bool)
+	for _, e := range dnsEntries {
+		if e.Name != record.Name || e.Type != record.Type {
+			continue
+		}
+		if !owned[e.Content] && !desired[e.Content] {
+			continue
+		}
+		if desired[e.Content] && e.Expire == record.Expire && !present[e.Content] {
+			present[e.Content] = true
+			continue
+		}
+		remove = append(remove, e)
+	}
+
+	for _, c := range record.Content {
+		if present[c] {
+			continue
+		}
+		present[c] = true
+		add = append(add, domain.DNSEntry{
+			Name:    record.Name,
+			Expire:  record.Expire,
+			Type:    record.Type,
+			Content: c,
+		})
+	}
+
+	if record.OwnerID == "" && previousOwnerID == "" {
+		return remove, add
+	}
+
+	registered := make(map[string]bool)
+	if record.OwnerID != "" {
+		for _, c := range record.Content {
+			registered[dnsOwnerRegistryContent(record.OwnerID, record.Type, c)] = false
+		}
+	}
+
+	registryName := dnsOwnerRegistryName(record.Name)
+	for _, e := range dnsEntries {
+		if e.Name != registryName || e.Type != "TXT" {
+			continue
+		}
+		current, ok := registered[e.Content]
+		if ok && !current && e.Expire == record.Expire {
+			registered[e.Content] = true
+			continue
+		}
+		if ok || (previousOwnerID != "" && strings.HasPrefix(e.Content, dnsOwnerRegistryOwnerPrefix(previousOwnerID, record.Type))) {
+			remove = append(remove, e)
+		}
+	}
+
+	if record.OwnerID != "" {
+		for _, c := range record.Content {
+			content := dnsOwnerRegistryContent(record.OwnerID, record.Type, c)
+			if registered[content] {
+				continue
+			}
+			registered[content] = true
+			add = append(add, domain.DNSEntry{
+				Name:    registryName,
+				Expire:  record.Expire,
+				Type:    "TXT",
+				Content: content,
+			})
+		}
+	}
+
+	return remove, add
+}
+
+// Returns the entries that are not in remove, each entry in remove only
+// matching a single entry.
+func withoutDNSEntries(dnsEntries []domain.DNSEntry, remove []domain.DNSEntry) []domain.DNSEntry {
+	removed := make(map[domain.DNSEntry]int)
+	for _, e := range remove {
+		removed[e]++
+	}
+
+	var entries []domain.DNSEntry
+	for _, e := range dnsEntries {
+		if removed[e] > 0 {
+			removed[e]--
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// Returns the entries that do not have the name, type and content of an entry
+// in remove, regardless of their expire, so entries whose TTL was changed
+// elsewhere are removed as well.
+func withoutDNSEntryContent(dnsEntries []domain.DNSEntry, remove []domain.DNSEntry) []domain.DNSEntry {
+	type key struct{ name, entryType, content string }
+	removed := make(map[key]bool)
+	for _, e := range remove {
+		removed[key{e.Name, e.Type, e.Content}] = true
+	}
+
+	var entries []domain.DNSEntry
+	for _, e := range dnsEntries {
+		if !removed[key{e.Name, e.Type, e.Content}] {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func expandStringSet(set *schema.Set) []string {
+	values := make([]string, set.Len())
+	for i, v := range set.List() {
+		values[i] = v.(string)
+	}
+	return values
+}
+
+// Whether both lists contain the same entries, regardless of order
+func sameDNSEntries(a []domain.DNSEntry, b []domain.DNSEntry) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return len(withoutDNSEntries(a, b)) == 0
+}
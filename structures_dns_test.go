@@ -0,0 +1,84 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/transip/gotransip/v6/domain"
+)
+
+func TestDNSRecordChangesLeavesUnownedEntries(t *testing.T) {
+	dnsEntries := []domain.DNSEntry{
+		{Name: "www", Expire: 300, Type: "A", Content: "192.0.2.1"},
+		{Name: "www", Expire: 300, Type: "A", Content: "192.0.2.2"},
+		{Name: "www", Expire: 300, Type: "A", Content: "192.0.2.3"},
+		{Name: "www", Expire: 300, Type: "AAAA", Content: "2001:db8::1"},
+	}
+	record := dnsRecord{Name: "www", Expire: 300, Type: "A", Content: []string{"192.0.2.2", "192.0.2.4"}}
+	owned := map[string]bool{"192.0.2.1": true, "192.0.2.2": true}
+
+	remove, add := dnsRecordChanges(dnsEntries, record, owned, "")
+
+	expectedRemove := []domain.DNSEntry{dnsEntries[0]}
+	expectedAdd := []domain.DNSEntry{{Name: "www", Expire: 300, Type: "A", Content: "192.0.2.4"}}
+	if !reflect.DeepEqual(remove, expectedRemove) {
+		t.Errorf("expected remove %v, got %v", expectedRemove, remove)
+	}
+	if !reflect.DeepEqual(add, expectedAdd) {
+		t.Errorf("expected add %v, got %v", expectedAdd, add)
+	}
+}
+
+func TestDNSRecordChangesExpire(t *testing.T) {
+	dnsEntries := []domain.DNSEntry{
+		{Name: "www", Expire: 300, Type: "A", Content: "192.0.2.1"},
+	}
+	record := dnsRecord{Name: "www", Expire: 60, Type: "A", Content: []string{"192.0.2.1"}}
+
+	remove, add := dnsRecordChanges(dnsEntries, record, map[string]bool{"192.0.2.1": true}, "")
+
+	if len(remove) != 1 || len(add) != 1 || add[0].Expire != 60 {
+		t.Errorf("expected entry to be replaced, got remove %v and add %v", remove, add)
+	}
+}
+
+func TestDNSRecordOwnerRegistry(t *testing.T) {
+	record := dnsRecord{Name: "www", Expire: 300, Type: "A", Content: []string{"192.0.2.1"}, OwnerID: "first"}
+	dnsEntries := []domain.DNSEntry{
+		{Name: "www", Expire: 300, Type: "A", Content: "192.0.2.2"},
+		{Name: "_terraform-owner.www", Expire: 300, Type: "TXT", Content: dnsOwnerRegistryContent("second", "A", "192.0.2.2")},
+	}
+
+	remove, add := dnsRecordChanges(dnsEntries, record, map[string]bool{}, "")
+	if len(remove) != 0 {
+		t.Errorf("expected no entries to be removed, got %v", remove)
+	}
+	dnsEntries = append(dnsEntries, add...)
+
+	owned := dnsRecordOwnedContent(dnsEntries, "www", "A", "first", nil)
+	if !reflect.DeepEqual(owned, map[string]bool{"192.0.2.1": true}) {
+		t.Errorf("expected only own content to be owned, got %v", owned)
+	}
+
+	// removing all content only removes own entries and registrations
+	record.Content = nil
+	remove, add = dnsRecordChanges(dnsEntries, record, owned, "first")
+	if len(add) != 0 {
+		t.Errorf("expected no entries to be added, got %v", add)
+	}
+	if !reflect.DeepEqual(withoutDNSEntries(dnsEntries, remove), dnsEntries[:2]) {
+		t.Errorf("expected only entries of other owners to remain, got %v", withoutDNSEntries(dnsEntries, remove))
+	}
+}
+
+func TestDNSOwnerRegistryName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"@":     "_terraform-owner",
+		"www":   "_terraform-owner.www",
+		"*.dev": "_terraform-owner._wildcard.dev",
+	} {
+		if actual := dnsOwnerRegistryName(name); actual != expected {
+			t.Errorf("expected %q for %q, got %q", expected, name, actual)
+		}
+	}
+}
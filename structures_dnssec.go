@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// DNSKEY flags, see https://www.iana.org/assignments/dnskey-flags
+var dnssecFlags = []int{
+	256, // Zone Signing Key
+	257, // Key Signing Key (Secure Entry Point)
+}
+
+// Flag of keys that sign the DNSKEY set, which are referred to by DS records
+const dnssecFlagSEP = 257
+
+// DNSSEC algorithms that can be used for zone signing, see
+// https://www.iana.org/assignments/dns-sec-alg-numbers. The DSA, RSASHA1 and
+// ECC-GOST algorithms are left out as RFC 8624 recommends against signing
+// with them.
+var dnssecAlgorithms = []int{
+	8,  // RSASHA256
+	10, // RSASHA512
+	13, // ECDSAP256SHA256
+	14, // ECDSAP384SHA384
+	15, // ED25519
+	16, // ED448
+}
+
+// DS digest types, see https://www.iana.org/assignments/ds-rr-types
+var dsDigestTypes = map[int]func() hash.Hash{
+	2: sha256.New,
+	4: sha512.New384,
+}
+
+// The RDATA of a DNSKEY record: flags, protocol (always 3), algorithm and key
+func dnskeyRDATA(flags int, algorithm int, publicKey string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(publicKey), ""))
+	if err != nil {
+		return nil, fmt.Errorf("public key is not valid base64: %s", err)
+	}
+	if len(key) == 0 {
+		return nil, fmt.Errorf("public key is empty")
+	}
+
+	rdata := make([]byte, 4, 4+len(key))
+	binary.BigEndian.PutUint16(rdata, uint16(flags))
+	rdata[2] = 3
+	rdata[3] = byte(algorithm)
+	return append(rdata, key...), nil
+}
+
+// Compute the key tag of a DNSKEY as described in RFC 4034, Appendix B
+func dnssecKeyTag(flags int, algorithm int, publicKey string) (int, error) {
+	rdata, err := dnskeyRDATA(flags, algorithm, publicKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var ac uint32
+	for i, b := range rdata {
+		if i&1 == 1 {
+			ac += uint32(b)
+		} else {
+			ac += uint32(b) << 8
+		}
+	}
+	ac += ac >> 16 & 0xFFFF
+	return int(ac & 0xFFFF), nil
+}
+
+// Compute the DS record digest of a DNSKEY for the domain as described in
+// RFC 4034, section 5.1.4.
+func dnssecDSDigest(domainName string, flags int, algorithm int, publicKey string, digestType int) (string, error) {
+	newHash, ok := dsDigestTypes[digestType]
+	if !ok {
+		return "", fmt.Errorf("unsupported digest type %d", digestType)
+	}
+
+	rdata, err := dnskeyRDATA(flags, algorithm, publicKey)
+	if err != nil {
+		return "", err
+	}
+
+	// owner name in canonical wire format
+	var owner []byte
+	for _, label := range strings.Split(normalizeDomainName(domainName), ".") {
+		owner = append(owner, byte(len(label)))
+		owner = append(owner, label...)
+	}
+	owner = append(owner, 0)
+
+	h := newHash()
+	h.Write(owner)
+	h.Write(rdata)
+	return fmt.Sprintf("%X", h.Sum(nil)), nil
+}
+
+// DS records for a DNSKEY, one for every supported digest type. Only key
+// signing keys are published at the parent zone, so zone signing keys have no
+// DS records.
+func dnssecDSRecords(domainName string, flags int, algorithm int, publicKey string) ([]map[string]interface{}, error) {
+	if flags != dnssecFlagSEP {
+		return nil, nil
+	}
+
+	keyTag, err := dnssecKeyTag(flags, algorithm, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	for _, digestType := range []int{2, 4} {
+		digest, err := dnssecDSDigest(domainName, flags, algorithm, publicKey, digestType)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, map[string]interface{}{
+			"key_tag":     keyTag,
+			"algorithm":   algorithm,
+			"digest_type": digestType,
+			"digest":      digest,
+			"record":      fmt.Sprintf("%d %d %d %s", keyTag, algorithm, digestType, digest),
+		})
+	}
+	return records, nil
+}
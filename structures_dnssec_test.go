@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+)
+
+// Example key from RFC 4034, section 5.4 and RFC 4509, section 2.3
+const testDNSKEY = `AQOeiiR0GOMYkDshWoSKz9Xz fwJr1AYtsmx3TGkJaNXVbfi/ 2pHm822aJ5iI9BMzNXxeYCmZ
+	DRD99WYwYqUSdjMmmAphXdvx egXd/M5+X7OrzKBaMbCVdFLU Uh6DhweJBjEVv5f2wwjM9Xzc
+	nOf+EPbtG9DMBmADjFDc2w/r ljwvFw==`
+
+// Example key from RFC 6605, section 6.2
+const testDNSKEYP384 = `xKYaNhWdGOfJ+nPrL8/arkwf2EY3MDJ+SErKivBVSum1 w/egsXvSADtNJhyem5RCOpgQ6K8X1DRSEkrbYQ+OB+v8
+	/uX45NBwY8rp65F6Glur8I/mlVNgF6W/qTI37m40`
+
+func TestDNSSecKeyTag(t *testing.T) {
+	keyTag, err := dnssecKeyTag(256, 5, testDNSKEY)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyTag != 60485 {
+		t.Errorf("expected key tag 60485, got %d", keyTag)
+	}
+
+	keyTag, err = dnssecKeyTag(257, 14, testDNSKEYP384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyTag != 10771 {
+		t.Errorf("expected key tag 10771, got %d", keyTag)
+	}
+
+	if _, err := dnssecKeyTag(257, 13, "not base64!"); err == nil {
+		t.Error("expected error for invalid public key")
+	}
+}
+
+func TestDNSSecDSDigest(t *testing.T) {
+	digest, err := dnssecDSDigest("dskey.example.com.", 256, 5, testDNSKEY, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A"; digest != expected {
+		t.Errorf("expected SHA-256 digest %s, got %s", expected, digest)
+	}
+
+	digest, err = dnssecDSDigest("Example.NET", 257, 14, testDNSKEYP384, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A946DF983D6"; digest != expected {
+		t.Errorf("expected SHA-384 digest %s, got %s", expected, digest)
+	}
+}
+
+func TestDNSSecDSRecords(t *testing.T) {
+	records, err := dnssecDSRecords("example.net", 257, 14, testDNSKEYP384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 || records[1]["record"] != "10771 14 4 72D7B62976CE06438E9C0BF319013CF801F09ECC84B8D7E9495F27E305C6A9B0563A9B5F4D288405C3008A946DF983D6" {
+		t.Errorf("unexpected DS records for key signing key: %v", records)
+	}
+
+	records, err = dnssecDSRecords("example.net", 256, 14, testDNSKEYP384)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 0 {
+		t.Errorf("expected no DS records for zone signing key, got %v", records)
+	}
+}
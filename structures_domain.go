@@ -0,0 +1,350 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/repository"
+	"github.com/transip/gotransip/v6/rest"
+)
+
+// WHOIS contact types of a domain
+var whoisContactTypes = []string{"registrant", "administrative", "technical"}
+
+// Lowercase ISO 3166-1 2 letter country code
+var countryCodeRegexp = regexp.MustCompile(`^[a-z]{2}$`)
+
+// Company types accepted for WHOIS contacts of a company
+var whoisCompanyTypes = []string{
+	"BV", "BVI/O", "COOP", "CV", "EENMANSZAAK", "KERK", "NV", "OWM", "REDR",
+	"STICHTING", "VERENIGING", "VOF", "BEG", "BRO", "EESV", "ANDERS",
+}
+
+// Body of a domain registration or transfer. The gotransip Register and
+// Transfer types declare their contacts as account contacts, while the API
+// expects WHOIS contacts, so the request is posted directly.
+type domainOrder struct {
+	DomainName  string                `json:"domainName"`
+	AuthCode    string                `json:"authCode,omitempty"`
+	Contacts    []domain.WhoisContact `json:"contacts,omitempty"`
+	DNSEntries  []domain.DNSEntry     `json:"dnsEntries,omitempty"`
+	Nameservers []domain.Nameserver   `json:"nameservers,omitempty"`
+}
+
+func postDomainOrder(client repository.Client, order domainOrder) error {
+	return client.Post(rest.Request{Endpoint: "/domains", Body: &order})
+}
+
+func whoisContactSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"type": {
+				Type:         schema.TypeString,
+				Description:  "The type of this contact, 'registrant', 'administrative' or 'technical'",
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(whoisContactTypes, false),
+			},
+			"first_name": {
+				Type:        schema.TypeString,
+				Description: "The first name of this contact",
+				Required:    true,
+			},
+			"last_name": {
+				Type:        schema.TypeString,
+				Description: "The last name of this contact",
+				Required:    true,
+			},
+			"company_name": {
+				Type:        schema.TypeString,
+				Description: "The company name of this contact, in case of a company",
+				Optional:    true,
+			},
+			"company_kvk": {
+				Type:        schema.TypeString,
+				Description: "The kvk number of this contact, in case of a company",
+				Optional:    true,
+			},
+			"company_type": {
+				Type:         schema.TypeString,
+				Description:  "The type of company of this contact, in case of a company",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(whoisCompanyTypes, false),
+			},
+			"street": {
+				Type:        schema.TypeString,
+				Description: "The street of the address of this contact",
+				Required:    true,
+			},
+			"number": {
+				Type:        schema.TypeString,
+				Description: "The number part of the address of this contact",
+				Required:    true,
+			},
+			"postal_code": {
+				Type:        schema.TypeString,
+				Description: "The postal code part of the address of this contact",
+				Required:    true,
+			},
+			"city": {
+				Type:        schema.TypeString,
+				Description: "The city part of the address of this contact",
+				Required:    true,
+			},
+			"phone_number": {
+				Type:        schema.TypeString,
+				Description: "The phone number of this contact, for example '+31.612345678'",
+				Required:    true,
+			},
+			"fax_number": {
+				Type:        schema.TypeString,
+				Description: "The fax number of this contact",
+				Optional:    true,
+			},
+			"email": {
+				Type:        schema.TypeString,
+				Description: "The email address of this contact",
+				Required:    true,
+			},
+			"country": {
+				Type:         schema.TypeString,
+				Description:  "The country of this contact, as lowercase ISO 3166-1 2 letter country code",
+				Required:     true,
+				ValidateFunc: validation.StringMatch(countryCodeRegexp, "must be a lowercase ISO 3166-1 2 letter country code"),
+			},
+		},
+	}
+}
+
+func interfacesToWhoisContacts(interfaces []interface{}) []domain.WhoisContact {
+	contacts := make([]domain.WhoisContact, len(interfaces))
+	for i, v := range interfaces {
+		map_ := v.(map[string]interface{})
+		contacts[i] = domain.WhoisContact{
+			Type:        map_["type"].(string),
+			FirstName:   map_["first_name"].(string),
+			LastName:    map_["last_name"].(string),
+			CompanyName: map_["company_name"].(string),
+			CompanyKvk:  map_["company_kvk"].(string),
+			CompanyType: map_["company_type"].(string),
+			Street:      map_["street"].(string),
+			Number:      map_["number"].(string),
+			PostalCode:  map_["postal_code"].(string),
+			City:        map_["city"].(string),
+			PhoneNumber: map_["phone_number"].(string),
+			FaxNumber:   map_["fax_number"].(string),
+			Email:       map_["email"].(string),
+			Country:     map_["country"].(string),
+		}
+	}
+	return contacts
+}
+
+func whoisContactsToMaps(contacts []domain.WhoisContact) []map[string]interface{} {
+	maps := make([]map[string]interface{}, len(contacts))
+	for i, v := range contacts {
+		maps[i] = map[string]interface{}{
+			"type":         v.Type,
+			"first_name":   v.FirstName,
+			"last_name":    v.LastName,
+			"company_name": v.CompanyName,
+			"company_kvk":  v.CompanyKvk,
+			"company_type": v.CompanyType,
+			"street":       v.Street,
+			"number":       v.Number,
+			"postal_code":  v.PostalCode,
+			"city":         v.City,
+			"phone_number": v.PhoneNumber,
+			"fax_number":   v.FaxNumber,
+			"email":        v.Email,
+			"country":      v.Country,
+		}
+	}
+	return maps
+}
+
+func nameserverSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"hostname": {
+				Type:        schema.TypeString,
+				Description: "The hostname of this nameserver",
+				Required:    true,
+			},
+			"ipv4": {
+				Type:         schema.TypeString,
+				Description:  "Optional ipv4 glue record for this nameserver",
+				Optional:     true,
+				ValidateFunc: validation.IsIPv4Address,
+			},
+			"ipv6": {
+				Type:         schema.TypeString,
+				Description:  "Optional ipv6 glue record for this nameserver",
+				Optional:     true,
+				ValidateFunc: validation.IsIPv6Address,
+			},
+		},
+	}
+}
+
+func dnsEntrySchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:         schema.TypeString,
+				Description:  "The name of the dns entry, for example '@' or 'www'",
+				Required:     true,
+				ValidateFunc: validateDNSEntryName,
+			},
+			"expire": {
+				Type:        schema.TypeInt,
+				Description: "The expiration period of the dns entry, in seconds",
+				Optional:    true,
+				Default:     86400,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Description:  "The type of dns entry",
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(dnsEntryTypes, false),
+			},
+			"content": {
+				Type:        schema.TypeString,
+				Description: "The content of the dns entry, for example '10 mail', '127.0.0.1' or 'www'",
+				Required:    true,
+			},
+		},
+	}
+}
+
+func interfacesToDNSEntries(interfaces []interface{}) []domain.DNSEntry {
+	entries := make([]domain.DNSEntry, len(interfaces))
+	for i, v := range interfaces {
+		map_ := v.(map[string]interface{})
+		entries[i] = domain.DNSEntry{
+			Name:    normalizeDNSEntryName(map_["name"]),
+			Expire:  map_["expire"].(int),
+			Type:    map_["type"].(string),
+			Content: map_["content"].(string),
+		}
+	}
+	return entries
+}
+
+// The domain action running for the domain, nil when there is none.
+func getDomainAction(repository domain.Repository, domainName string) (*domain.Action, error) {
+	action, err := repository.GetDomainAction(domainName)
+	if err != nil {
+		// the API answers with not found when no action is running
+		var restErr *rest.Error
+		if errors.As(err, &restErr) && restErr.StatusCode == http.StatusNotFound {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get domain action of domain %q: %s", domainName, err)
+	}
+	if action.Name == "" {
+		return nil, nil
+	}
+	return &action, nil
+}
+
+// Disabling auto renew cancels the domain at the end of its term, which can
+// not be undone through the API, so it is refused while deletion protection is
+// enabled and enabling it again fails the plan.
+func checkDomainAutoRenew(d *schema.ResourceDiff) error {
+	if !d.NewValueKnown("auto_renew") || (d.Id() != "" && !d.HasChange("auto_renew")) {
+		return nil
+	}
+	name := d.Get("name").(string)
+	if !d.Get("auto_renew").(bool) {
+		if d.Get("deletion_protection").(bool) {
+			return fmt.Errorf("disabling auto_renew cancels domain %q at the end of its term, disable deletion_protection first", name)
+		}
+		return nil
+	}
+	if d.Id() != "" {
+		return fmt.Errorf("domain %q is cancelled at the end of its term, auto_renew can not be enabled again through the API, withdraw the cancellation in the TransIP control panel instead", name)
+	}
+	return nil
+}
+
+// Arguments that are only used when the domain is registered, with the
+// resource that manages them afterwards
+var domainRegistrationArguments = []struct{ key, resource string }{
+	{"contact", "transip_domain_contacts"},
+	{"nameserver", "transip_domain_nameservers"},
+	{"dns_entry", "transip_dns_record"},
+	{"purge_default_dns_entries", "transip_dns_record"},
+}
+
+// Changing registration arguments after the domain is registered would not
+// change the domain, so the plan fails instead of silently ignoring them.
+// Imported domains have no contacts, nameservers or DNS entries in state, the
+// configured ones are adopted.
+func checkDomainRegistrationChanges(d *schema.ResourceDiff) error {
+	for _, argument := range domainRegistrationArguments {
+		if !d.HasChange(argument.key) {
+			continue
+		}
+		old, _ := d.GetChange(argument.key)
+		if list, ok := old.([]interface{}); ok && len(list) == 0 {
+			continue
+		}
+		return fmt.Errorf("%s is only used to register domain %q and can not be changed afterwards, use the %s resource instead", argument.key, d.Id(), argument.resource)
+	}
+	return nil
+}
+
+func domainActionRetriesSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeInt,
+		Description:  "Number of times a failed domain action is retried automatically before giving up",
+		Optional:     true,
+		Default:      0,
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+}
+
+// Wait until the domain exists and no domain action, like a registration or
+// transfer, is running for it anymore. A failed action is retried with the
+// data of the order up to retries times, after that its message is returned.
+func waitForDomainAction(client repository.Client, domainName string, timeout time.Duration, retries int, order domainOrder) error {
+	repository := domain.Repository{Client: client}
+
+	return resource.Retry(timeout, func() *resource.RetryError {
+		if _, err := repository.GetByDomainName(domainName); err != nil {
+			return resource.RetryableError(err)
+		}
+
+		action, err := getDomainAction(repository, domainName)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if action == nil {
+			return nil
+		}
+		if action.HasFailed {
+			if retries <= 0 {
+				return resource.NonRetryableError(fmt.Errorf("domain action %q of domain %q failed: %s", action.Name, domainName, action.Message))
+			}
+			retries--
+
+			log.Printf("[WARN] terraform-provider-transip: domain action %q of domain %s failed, retrying: %s\n", action.Name, domainName, action.Message)
+			err = repository.RetryDomainAction(domainName, order.AuthCode, order.DNSEntries, order.Nameservers, order.Contacts)
+			if err != nil {
+				return resource.NonRetryableError(fmt.Errorf("failed to retry domain action %q of domain %q: %s", action.Name, domainName, err))
+			}
+			return resource.RetryableError(fmt.Errorf("domain action %q of domain %q is retried", action.Name, domainName))
+		}
+
+		log.Printf("[DEBUG] terraform-provider-transip: waiting for domain action %q of domain %s\n", action.Name, domainName)
+		return resource.RetryableError(fmt.Errorf("domain action %q of domain %q is still running", action.Name, domainName))
+	})
+}
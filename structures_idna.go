@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"golang.org/x/net/idna"
+)
+
+// IDNA profile for domain names, mapping unicode names like 'Bücher.nl' to
+// their lowercase punycode form 'xn--bcher-kva.nl' and validating all labels.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.VerifyDNSLength(true),
+	idna.Transitional(false),
+)
+
+// IDNA profile to display punycode labels back in unicode
+var idnaDisplayProfile = idna.New(idna.Transitional(false))
+
+// Labels of record names may also contain underscores, like '_dmarc'
+var asciiRecordLabelRegexp = regexp.MustCompile(`^(?i)[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?$`)
+
+// Convert the unicode labels of a name to punycode, ASCII labels are kept as
+// they are. Names that can not be converted are returned unchanged, so they
+// fail validation instead.
+func labelsToASCII(name string) string {
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		ascii, err := idnaProfile.ToASCII(label)
+		if err != nil {
+			return name
+		}
+		labels[i] = ascii
+	}
+	return strings.Join(labels, ".")
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// The unicode form of a domain name, for display only
+func domainNameToUnicode(name string) string {
+	unicode, err := idnaDisplayProfile.ToUnicode(name)
+	if err != nil {
+		return name
+	}
+	return unicode
+}
+
+// Validate a domain name, including its tld, in either unicode or punycode form
+func validateDomainName(v interface{}, k string) (ws []string, errs []error) {
+	name := strings.TrimSuffix(v.(string), ".")
+	if strings.Count(name, ".") < 1 {
+		errs = append(errs, fmt.Errorf("%q must be a domain name including its tld, got %q", k, v))
+		return
+	}
+	if _, err := idnaProfile.ToASCII(name); err != nil {
+		errs = append(errs, fmt.Errorf("%q is not a valid domain name: %s", k, err))
+	}
+	return
+}
+
+// Validate the name of a DNS entry relative to its domain, like '@', 'www',
+// '_dmarc' or the wildcard '*.dev'. Unicode labels are allowed as well.
+func validateDNSEntryName(v interface{}, k string) (ws []string, errs []error) {
+	name := v.(string)
+	if name == "@" {
+		return
+	}
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		switch {
+		case label == "*":
+			if i != 0 {
+				errs = append(errs, fmt.Errorf("%q may only have the wildcard '*' as its first label, got %q", k, name))
+			}
+		case strings.Contains(label, "*"):
+			errs = append(errs, fmt.Errorf("%q may only use the wildcard '*' as a whole label, got %q", k, name))
+		case isASCII(label):
+			if !asciiRecordLabelRegexp.MatchString(label) {
+				errs = append(errs, fmt.Errorf("%q has an invalid label %q, labels are 1 to 63 letters, digits, '_' or '-' and do not start or end with '-'", k, label))
+			}
+		default:
+			if _, err := idnaProfile.ToASCII(label); err != nil {
+				errs = append(errs, fmt.Errorf("%q has an invalid label %q: %s", k, label, err))
+			}
+		}
+	}
+	return
+}
+
+// Normalize a record name to the form used by the API, keeping ASCII labels
+// as they are so existing names do not change
+func normalizeDNSEntryName(v interface{}) string {
+	return labelsToASCII(v.(string))
+}
+
+func stateDomainName(v interface{}) string {
+	return normalizeDomainName(v.(string))
+}
+
+// Compare domain names in their normalized form, so names in states written
+// before they were normalized, like 'Example.com', do not replace the resource
+func suppressDomainNameDiff(k, old, new string, d *schema.ResourceData) bool {
+	return stateDomainName(old) == stateDomainName(new)
+}
+
+// Compare record names in their normalized form, for states written before
+// unicode labels were converted to punycode
+func suppressDNSEntryNameDiff(k, old, new string, d *schema.ResourceData) bool {
+	return normalizeDNSEntryName(old) == normalizeDNSEntryName(new)
+}
+
+func unicodeDomainSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The name of the domain in unicode, for internationalized domain names",
+		Computed:    true,
+	}
+}
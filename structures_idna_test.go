@@ -0,0 +1,118 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestNormalizeDomainName(t *testing.T) {
+	tests := map[string]string{
+		"example.com":          "example.com",
+		"Example.COM.":         "example.com",
+		"bücher.nl":            "xn--bcher-kva.nl",
+		"BÜCHER.nl":            "xn--bcher-kva.nl",
+		"xn--bcher-kva.nl":     "xn--bcher-kva.nl",
+		"*.dev.bücher.nl":      "*.dev.xn--bcher-kva.nl",
+		"_acme-challenge.a.nl": "_acme-challenge.a.nl",
+	}
+	for name, expected := range tests {
+		if got := normalizeDomainName(name); got != expected {
+			t.Errorf("normalizeDomainName(%q): expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestNormalizeDNSEntryName(t *testing.T) {
+	tests := map[string]string{
+		"@":        "@",
+		"www":      "www",
+		"WWW":      "WWW",
+		"*.dev":    "*.dev",
+		"bücher":   "xn--bcher-kva",
+		"*.Bücher": "*.xn--bcher-kva",
+	}
+	for name, expected := range tests {
+		if got := normalizeDNSEntryName(name); got != expected {
+			t.Errorf("normalizeDNSEntryName(%q): expected %q, got %q", name, expected, got)
+		}
+	}
+}
+
+func TestDomainNameToUnicode(t *testing.T) {
+	if got := domainNameToUnicode("xn--bcher-kva.nl"); got != "bücher.nl" {
+		t.Errorf("expected %q, got %q", "bücher.nl", got)
+	}
+	if got := domainNameToUnicode("example.com"); got != "example.com" {
+		t.Errorf("expected %q, got %q", "example.com", got)
+	}
+}
+
+func TestValidateDomainName(t *testing.T) {
+	valid := []string{"example.com", "example.com.", "bücher.nl", "xn--bcher-kva.nl", "sub.example.co.uk"}
+	invalid := []string{"example", "-example.com", "exa mple.com", "*.example.com", "_dmarc.example.com", "example..com"}
+
+	for _, name := range valid {
+		if _, errs := validateDomainName(name, "name"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", name, errs)
+		}
+	}
+	for _, name := range invalid {
+		if _, errs := validateDomainName(name, "name"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestValidateDNSEntryName(t *testing.T) {
+	valid := []string{"@", "www", "WWW", "*", "*.dev", "_dmarc", "_acme-challenge.www", "bücher", "a.b.c"}
+	invalid := []string{"", "dev.*", "*dev", "w*w", "-www", "www-", "exa mple", "a..b", "www.", "@.www"}
+
+	for _, name := range valid {
+		if _, errs := validateDNSEntryName(name, "name"); len(errs) > 0 {
+			t.Errorf("expected %q to be valid, got %v", name, errs)
+		}
+	}
+	for _, name := range invalid {
+		if _, errs := validateDNSEntryName(name, "name"); len(errs) == 0 {
+			t.Errorf("expected %q to be invalid", name)
+		}
+	}
+}
+
+func TestSuppressDomainNameDiff(t *testing.T) {
+	tests := []struct {
+		old, new string
+		expected bool
+	}{
+		{"Example.com", "example.com", true},
+		{"example.com.", "example.com", true},
+		{"bücher.nl", "xn--bcher-kva.nl", true},
+		{"example.com", "example.nl", false},
+		{"", "example.com", false},
+	}
+	for _, test := range tests {
+		if got := suppressDomainNameDiff("domain", test.old, test.new, nil); got != test.expected {
+			t.Errorf("suppressDomainNameDiff(%q, %q): expected %v, got %v", test.old, test.new, test.expected, got)
+		}
+	}
+}
+
+func TestDomainNameDiffOldState(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID:         "Example.com",
+		Attributes: map[string]string{"id": "Example.com", "domain": "Example.com"},
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"domain":       "Example.com",
+		"company_name": "Example",
+	})
+
+	diff, err := resourceDomainBranding().Diff(state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.RequiresNew() {
+		t.Errorf("expected a mixed-case name in an old state not to replace the resource, got %v", diff)
+	}
+}
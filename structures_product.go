@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/product"
+)
+
+func productSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Description: "The name of the product.",
+			Computed:    true,
+		},
+		"description": {
+			Type:        schema.TypeString,
+			Description: "Describes this product.",
+			Computed:    true,
+		},
+		"price": {
+			Type:        schema.TypeInt,
+			Description: "Price in cents.",
+			Computed:    true,
+		},
+		"recurring_price": {
+			Type:        schema.TypeInt,
+			Description: "The recurring price for the product in cents.",
+			Computed:    true,
+		},
+	}
+}
+
+func productsToMaps(products []product.Product) []map[string]interface{} {
+	maps := make([]map[string]interface{}, len(products))
+	for i, p := range products {
+		maps[i] = map[string]interface{}{
+			"name":            p.Name,
+			"description":     p.Description,
+			"price":           p.Price,
+			"recurring_price": p.RecurringPrice,
+		}
+	}
+	return maps
+}
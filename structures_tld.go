@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/transip/gotransip/v6/domain"
+	"github.com/transip/gotransip/v6/rest"
+)
+
+// Schema of the attributes of a TLD, shared by the TLD data sources
+func tldSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"price": {
+			Type:        schema.TypeInt,
+			Description: "Price of registering a domain under the TLD, in cents.",
+			Computed:    true,
+		},
+		"recurring_price": {
+			Type:        schema.TypeInt,
+			Description: "Price of renewing a domain under the TLD, in cents.",
+			Computed:    true,
+		},
+		"min_length": {
+			Type:        schema.TypeInt,
+			Description: "The minimum amount of characters of a domain name under the TLD.",
+			Computed:    true,
+		},
+		"max_length": {
+			Type:        schema.TypeInt,
+			Description: "The maximum amount of characters of a domain name under the TLD.",
+			Computed:    true,
+		},
+		"registration_period_length": {
+			Type:        schema.TypeInt,
+			Description: "Length in months of each registration or renewal period.",
+			Computed:    true,
+		},
+		"cancel_time_frame": {
+			Type:        schema.TypeInt,
+			Description: "Number of days a domain needs to be cancelled before the renewal date.",
+			Computed:    true,
+		},
+		"capabilities": {
+			Type:        schema.TypeList,
+			Description: "The things that can be done with a domain under the TLD, like 'canRegister', 'canSetLock' or 'supportsDnsSec'.",
+			Computed:    true,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		},
+		"can_register": {
+			Type:        schema.TypeBool,
+			Description: "Whether domains under the TLD can be registered.",
+			Computed:    true,
+		},
+		"can_set_lock": {
+			Type:        schema.TypeBool,
+			Description: "Whether domains under the TLD support transfer locking.",
+			Computed:    true,
+		},
+		"requires_auth_code": {
+			Type:        schema.TypeBool,
+			Description: "Whether transferring domains under the TLD requires an authcode.",
+			Computed:    true,
+		},
+		"supports_dnssec": {
+			Type:        schema.TypeBool,
+			Description: "Whether domains under the TLD support DNSSEC.",
+			Computed:    true,
+		},
+	}
+}
+
+func tldHasCapability(tld domain.Tld, capability string) bool {
+	for _, c := range tld.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func tldToMap(tld domain.Tld) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                       tld.Name,
+		"price":                      tld.Price,
+		"recurring_price":            tld.RecurringPrice,
+		"min_length":                 tld.MinLength,
+		"max_length":                 tld.MaxLength,
+		"registration_period_length": tld.RegistrationPeriodLength,
+		"cancel_time_frame":          tld.CancelTimeFrame,
+		"capabilities":               tld.Capabilities,
+		"can_register":               tldHasCapability(tld, "canRegister"),
+		"can_set_lock":               tldHasCapability(tld, "canSetLock"),
+		"requires_auth_code":         tldHasCapability(tld, "requiresAuthCode"),
+		"supports_dnssec":            tldHasCapability(tld, "supportsDnsSec"),
+	}
+}
+
+// Candidate TLDs of a domain name, longest first. For 'example.co.uk' these
+// are '.co.uk' and '.uk'.
+func domainTLDCandidates(domainName string) []string {
+	labels := strings.Split(normalizeDomainName(domainName), ".")
+	var candidates []string
+	for i := 1; i < len(labels); i++ {
+		candidates = append(candidates, "."+strings.Join(labels[i:], "."))
+	}
+	return candidates
+}
+
+// The longest TLD offered by TransIP the domain name is part of
+func getDomainTLD(repository domain.Repository, domainName string) (domain.Tld, error) {
+	for _, candidate := range domainTLDCandidates(domainName) {
+		tld, err := repository.GetTLDByTLD(candidate)
+		if err == nil {
+			return tld, nil
+		}
+		var restErr *rest.Error
+		if !errors.As(err, &restErr) || restErr.StatusCode != http.StatusNotFound {
+			return domain.Tld{}, fmt.Errorf("failed to get TLD %q: %s", candidate, err)
+		}
+	}
+	return domain.Tld{}, fmt.Errorf("the TLD of domain %q is not offered by TransIP", domainName)
+}
+
+// Check whether a domain name can be registered under the TLD
+func validateDomainNameForTLD(domainName string, tld domain.Tld) error {
+	label := strings.TrimSuffix(normalizeDomainName(domainName), tld.Name)
+	if strings.Contains(label, ".") || label == "" {
+		return fmt.Errorf("domain %q must be a single label followed by the TLD %s", domainName, tld.Name)
+	}
+	if tld.MinLength > 0 && len(label) < tld.MinLength {
+		return fmt.Errorf("domain %q is too short, domains under %s need at least %d characters", domainName, tld.Name, tld.MinLength)
+	}
+	if tld.MaxLength > 0 && len(label) > tld.MaxLength {
+		return fmt.Errorf("domain %q is too long, domains under %s can have at most %d characters", domainName, tld.Name, tld.MaxLength)
+	}
+	if !tldHasCapability(tld, "canRegister") {
+		return fmt.Errorf("domains under %s can not be registered", tld.Name)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/transip/gotransip/v6/domain"
+)
+
+func TestDomainTLDCandidates(t *testing.T) {
+	expected := []string{".co.uk", ".uk"}
+	if actual := domainTLDCandidates("Example.co.uk."); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestValidateDomainNameForTLD(t *testing.T) {
+	tld := domain.Tld{Name: ".nl", MinLength: 2, MaxLength: 10, Capabilities: []string{"canRegister"}}
+
+	if err := validateDomainNameForTLD("example.nl", tld); err != nil {
+		t.Errorf("expected example.nl to be valid: %s", err)
+	}
+
+	for _, name := range []string{"a.nl", "waytoolongexample.nl", "www.example.nl", ".nl"} {
+		if err := validateDomainNameForTLD(name, tld); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+
+	tld.Capabilities = nil
+	if err := validateDomainNameForTLD("example.nl", tld); err == nil {
+		t.Error("expected error for TLD that can not be registered")
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/transip/gotransip/v6/ipaddress"
 	"github.com/transip/gotransip/v6/vps"
 )
@@ -110,3 +112,48 @@ func vpsFirewallRuleFlatten(rule *vps.FirewallRule) map[string]interface{} {
 	}
 	return res
 }
+
+// The names of the active addons, sorted. A VPS can have the same addon more
+// than once, like two extra ip addresses.
+func activeAddonNames(addons vps.Addons) []string {
+	names := make([]string, len(addons.Active))
+	for i, addon := range addons.Active {
+		names[i] = addon.Name
+	}
+	sort.Strings(names)
+	return names
+}
+
+func expandAddons(addons []interface{}) []string {
+	names := make([]string, len(addons))
+	for i, addon := range addons {
+		names[i] = addon.(string)
+	}
+	return names
+}
+
+// The addons in a that are not in b, counting addons that occur more than once
+func addonsDifference(a []string, b []string) []string {
+	count := make(map[string]int)
+	for _, addon := range b {
+		count[addon]++
+	}
+	var difference []string
+	for _, addon := range a {
+		if count[addon] > 0 {
+			count[addon]--
+			continue
+		}
+		difference = append(difference, addon)
+	}
+	sort.Strings(difference)
+	return difference
+}
+
+// The order of the addons does not matter, only how often each one occurs
+func suppressAddonsOrder(k, old, new string, d *schema.ResourceData) bool {
+	o, n := d.GetChange("addons")
+	oldAddons := expandAddons(o.([]interface{}))
+	newAddons := expandAddons(n.([]interface{}))
+	return len(oldAddons) == len(newAddons) && len(addonsDifference(oldAddons, newAddons)) == 0
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/transip/gotransip/v6/domain"
+)
+
+// Render DNS entries as an RFC 1035 zone file for the domain. Names and
+// content are written relative to the domain, just like TransIP returns them.
+func renderZoneFile(domainName string, dnsEntries []domain.DNSEntry) string {
+	entries := make([]domain.DNSEntry, len(dnsEntries))
+	copy(entries, dnsEntries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			if entries[i].Name == "@" || entries[j].Name == "@" {
+				return entries[i].Name == "@"
+			}
+			return entries[i].Name < entries[j].Name
+		}
+		if entries[i].Type != entries[j].Type {
+			return entries[i].Type < entries[j].Type
+		}
+		return entries[i].Content < entries[j].Content
+	})
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "$ORIGIN %s.\n", normalizeDomainName(domainName))
+	for _, e := range entries {
+		content := e.Content
+		if e.Type == "TXT" {
+			content = quoteZoneFileText(content)
+		}
+		fmt.Fprintf(&b, "%s\t%d\tIN\t%s\t%s\n", e.Name, e.Expire, e.Type, content)
+	}
+	return b.String()
+}
+
+// TXT content is split in quoted strings of at most 255 characters.
+func quoteZoneFileText(text string) string {
+	var parts []string
+	for {
+		n := len(text)
+		if n > 255 {
+			n = 255
+		}
+		part := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(text[:n])
+		parts = append(parts, `"`+part+`"`)
+		text = text[n:]
+		if text == "" {
+			return strings.Join(parts, " ")
+		}
+	}
+}
+
+type zoneFileToken struct {
+	value  string
+	quoted bool
+}
+
+// Split a zone file in lines of tokens, joining lines between parentheses
+// and dropping comments. The line number of the start of each line is kept
+// for error messages. A line starting with whitespace has an empty first
+// token, which means the owner of the previous record is used.
+func tokenizeZoneFile(text string) ([][]zoneFileToken, []int, error) {
+	var lines [][]zoneFileToken
+	var lineNumbers []int
+
+	var line []zoneFileToken
+	lineNumber, current := 1, 1
+	depth := 0
+	startOfLine := true
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '\n':
+			current++
+			if depth == 0 {
+				if len(line) > 0 {
+					lines = append(lines, line)
+					lineNumbers = append(lineNumbers, lineNumber)
+				}
+				line = nil
+				lineNumber = current
+				startOfLine = true
+			}
+			continue
+		case c == ';':
+			for i+1 < len(text) && text[i+1] != '\n' {
+				i++
+			}
+		case c == '(':
+			depth++
+		case c == ')':
+			if depth == 0 {
+				return nil, nil, fmt.Errorf("line %d: unexpected ')'", current)
+			}
+			depth--
+		case c == ' ' || c == '\t' || c == '\r':
+			if startOfLine && len(line) == 0 {
+				line = append(line, zoneFileToken{})
+			}
+		case c == '"':
+			var b strings.Builder
+			closed := false
+			for i++; i < len(text); i++ {
+				if text[i] == '\\' && i+1 < len(text) {
+					i++
+					b.WriteByte(text[i])
+					continue
+				}
+				if text[i] == '"' {
+					closed = true
+					break
+				}
+				if text[i] == '\n' {
+					current++
+				}
+				b.WriteByte(text[i])
+			}
+			if !closed {
+				return nil, nil, fmt.Errorf("line %d: unterminated quoted string", lineNumber)
+			}
+			line = append(line, zoneFileToken{value: b.String(), quoted: true})
+		default:
+			start := i
+			for i+1 < len(text) && !strings.ContainsRune(" \t\r\n;()\"", rune(text[i+1])) {
+				i++
+			}
+			line = append(line, zoneFileToken{value: text[start : i+1]})
+		}
+		startOfLine = false
+	}
+
+	if depth != 0 {
+		return nil, nil, fmt.Errorf("line %d: missing ')'", lineNumber)
+	}
+	if len(line) > 0 {
+		lines = append(lines, line)
+		lineNumbers = append(lineNumbers, lineNumber)
+	}
+
+	// drop lines that only consist of whitespace
+	var result [][]zoneFileToken
+	var resultNumbers []int
+	for i, line := range lines {
+		if len(line) == 1 && line[0].value == "" && !line[0].quoted {
+			continue
+		}
+		result = append(result, line)
+		resultNumbers = append(resultNumbers, lineNumbers[i])
+	}
+	return result, resultNumbers, nil
+}
+
+// Parse a TTL in seconds, or with BIND style units like '1h30m'.
+func parseZoneFileTTL(value string) (int, bool) {
+	if ttl, err := strconv.Atoi(value); err == nil {
+		return ttl, ttl >= 0
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+	ttl, number := 0, ""
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c >= '0' && c <= '9' {
+			number += string(c)
+			continue
+		}
+		unit, ok := units[c|0x20]
+		if !ok || number == "" {
+			return 0, false
+		}
+		n, _ := strconv.Atoi(number)
+		ttl += n * unit
+		number = ""
+	}
+	if number != "" {
+		return 0, false
+	}
+	return ttl, true
+}
+
+// Qualify a name from the zone file relative to the current origin.
+func zoneFileFQDN(name string, origin string) string {
+	switch {
+	case name == "@":
+		return origin
+	case strings.HasSuffix(name, "."):
+		return strings.ToLower(name)
+	default:
+		return strings.ToLower(name) + "." + origin
+	}
+}
+
+// Make a fully qualified name relative to the domain, like TransIP uses them.
+// Names outside of the domain are kept fully qualified.
+func zoneFileRelativeName(fqdn string, domainName string) (string, bool) {
+	origin := normalizeDomainName(domainName) + "."
+	if fqdn == origin {
+		return "@", true
+	}
+	if strings.HasSuffix(fqdn, "."+origin) {
+		return strings.TrimSuffix(fqdn, "."+origin), true
+	}
+	return fqdn, false
+}
+
+// Parse an RFC 1035 zone file into TransIP DNS entries for the domain. SOA
+// records are managed by TransIP and skipped, other record types that can not
+// be managed through the API result in an error.
+func parseZoneFile(domainName string, text string, defaultTTL int) ([]domain.DNSEntry, error) {
+	lines, lineNumbers, err := tokenizeZoneFile(text)
+	if err != nil {
+		return nil, err
+	}
+
+	origin := normalizeDomainName(domainName) + "."
+	ttl := defaultTTL
+	owner := ""
+
+	supported := make(map[string]bool)
+	for _, t := range dnsEntryTypes {
+		supported[t] = true
+	}
+
+	var entries []domain.DNSEntry
+	for i, tokens := range lines {
+		lineNumber := lineNumbers[i]
+		errorf := func(format string, a ...interface{}) error {
+			return fmt.Errorf("line %d: %s", lineNumber, fmt.Sprintf(format, a...))
+		}
+
+		switch strings.ToUpper(tokens[0].value) {
+		case "$ORIGIN":
+			if len(tokens) != 2 {
+				return nil, errorf("$ORIGIN requires a single name")
+			}
+			origin = zoneFileFQDN(tokens[1].value, origin)
+			continue
+		case "$TTL":
+			if len(tokens) != 2 {
+				return nil, errorf("$TTL requires a single value")
+			}
+			var ok bool
+			if ttl, ok = parseZoneFileTTL(tokens[1].value); !ok {
+				return nil, errorf("invalid TTL %q", tokens[1].value)
+			}
+			continue
+		case "$INCLUDE", "$GENERATE":
+			return nil, errorf("%s is not supported", tokens[0].value)
+		}
+
+		// owner, optionally followed by TTL and class in any order, and the type
+		if tokens[0].value != "" {
+			owner = zoneFileFQDN(tokens[0].value, origin)
+		} else if owner == "" {
+			return nil, errorf("record without owner name")
+		}
+		tokens = tokens[1:]
+
+		entryTTL := ttl
+		for len(tokens) > 0 {
+			if strings.EqualFold(tokens[0].value, "IN") {
+				tokens = tokens[1:]
+				continue
+			}
+			if v, ok := parseZoneFileTTL(tokens[0].value); ok && !tokens[0].quoted {
+				entryTTL = v
+				tokens = tokens[1:]
+				continue
+			}
+			break
+		}
+		if len(tokens) == 0 {
+			return nil, errorf("missing record type")
+		}
+		entryType := strings.ToUpper(tokens[0].value)
+		rdata := tokens[1:]
+
+		if entryType == "SOA" {
+			continue
+		}
+		if !supported[entryType] {
+			return nil, errorf("record type %s is not supported by TransIP, supported types are %s", tokens[0].value, strings.Join(dnsEntryTypes, ", "))
+		}
+		if len(rdata) == 0 {
+			return nil, errorf("missing content for %s record", entryType)
+		}
+
+		name, ok := zoneFileRelativeName(owner, domainName)
+		if !ok {
+			return nil, errorf("name %s is not part of domain %s", owner, domainName)
+		}
+
+		// names in the content are made relative to the domain as well
+		targetIndex := map[string]int{"CNAME": 0, "NS": 0, "ALIAS": 0, "MX": 1, "SRV": 3}
+		var content string
+		if entryType == "TXT" {
+			var parts []string
+			for _, t := range rdata {
+				parts = append(parts, t.value)
+			}
+			content = strings.Join(parts, "")
+		} else {
+			index, hasTarget := targetIndex[entryType]
+			var parts []string
+			for j, t := range rdata {
+				value := t.value
+				switch {
+				case hasTarget && j == index:
+					value, _ = zoneFileRelativeName(zoneFileFQDN(value, origin), domainName)
+				case t.quoted:
+					value = `"` + value + `"`
+				}
+				parts = append(parts, value)
+			}
+			if hasTarget && len(rdata) != index+1 {
+				return nil, errorf("%s record requires %d values, got %d", entryType, index+1, len(rdata))
+			}
+			content = strings.Join(parts, " ")
+		}
+
+		entries = append(entries, domain.DNSEntry{
+			Name:    name,
+			Expire:  entryTTL,
+			Type:    entryType,
+			Content: content,
+		})
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/transip/gotransip/v6/domain"
+)
+
+func TestParseZoneFile(t *testing.T) {
+	zoneFile := `
+$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns0.transip.net. hostmaster.transip.nl. (
+		2024010101 ; serial
+		3h 15m 1w 1d )
+@		IN	A	192.0.2.1
+		300	IN	AAAA	2001:db8::1
+www		IN	CNAME	@
+mail.example.com.	1d	MX	10 mail
+@		TXT	"v=spf1 " "include:_spf.example.org -all"
+@		CAA	0 issue "letsencrypt.org"
+_sip._tcp	SRV	10 100 5060 sip.example.org.
+
+$ORIGIN dev.example.com.
+*	IN	A	192.0.2.2
+`
+
+	entries, err := parseZoneFile("example.com", zoneFile, 86400)
+	if err != nil {
+		t.Fatalf("failed to parse zone file: %s", err)
+	}
+
+	expected := []domain.DNSEntry{
+		{Name: "@", Expire: 3600, Type: "A", Content: "192.0.2.1"},
+		{Name: "@", Expire: 300, Type: "AAAA", Content: "2001:db8::1"},
+		{Name: "www", Expire: 3600, Type: "CNAME", Content: "@"},
+		{Name: "mail", Expire: 86400, Type: "MX", Content: "10 mail"},
+		{Name: "@", Expire: 3600, Type: "TXT", Content: "v=spf1 include:_spf.example.org -all"},
+		{Name: "@", Expire: 3600, Type: "CAA", Content: `0 issue "letsencrypt.org"`},
+		{Name: "_sip._tcp", Expire: 3600, Type: "SRV", Content: "10 100 5060 sip.example.org."},
+		{Name: "*.dev", Expire: 3600, Type: "A", Content: "192.0.2.2"},
+	}
+	if !reflect.DeepEqual(entries, expected) {
+		t.Errorf("\n\nexpected:\n\n%v\n\ngot:\n\n%v", expected, entries)
+	}
+}
+
+func TestParseZoneFileErrors(t *testing.T) {
+	for zoneFile, expected := range map[string]string{
+		"@ IN PTR host.example.com.":          "line 1: record type PTR is not supported by TransIP",
+		"\n\nwww.example.org. IN A 192.0.2.1": "line 3: name www.example.org. is not part of domain example.com",
+		"@ IN TXT \"unterminated":             "line 1: unterminated quoted string",
+		"@ IN MX 10":                          "line 1: MX record requires 2 values, got 1",
+		" IN A 192.0.2.1":                     "line 1: record without owner name",
+	} {
+		_, err := parseZoneFile("example.com", zoneFile, 86400)
+		if err == nil || !strings.HasPrefix(err.Error(), expected) {
+			t.Errorf("expected error %q for %q, got %v", expected, zoneFile, err)
+		}
+	}
+}
+
+func TestRenderZoneFileRoundTrip(t *testing.T) {
+	dnsEntries := []domain.DNSEntry{
+		{Name: "www", Expire: 300, Type: "CNAME", Content: "@"},
+		{Name: "@", Expire: 86400, Type: "TXT", Content: `say "hello"` + strings.Repeat("x", 300)},
+		{Name: "@", Expire: 86400, Type: "A", Content: "192.0.2.1"},
+		{Name: "@", Expire: 86400, Type: "ALIAS", Content: "lb.example.org."},
+	}
+
+	zoneFile := renderZoneFile("example.com", dnsEntries)
+	if !strings.HasPrefix(zoneFile, "$ORIGIN example.com.\n@\t86400\tIN\tA\t192.0.2.1\n") {
+		t.Errorf("unexpected zone file:\n%s", zoneFile)
+	}
+
+	entries, err := parseZoneFile("example.com", zoneFile, 86400)
+	if err != nil {
+		t.Fatalf("failed to parse rendered zone file: %s", err)
+	}
+	if !sameDNSEntries(entries, dnsEntries) {
+		t.Errorf("\n\nexpected:\n\n%v\n\ngot:\n\n%v", dnsEntries, entries)
+	}
+}